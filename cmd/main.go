@@ -1,54 +1,94 @@
 package main
 
 import (
-	"market/internal/domain/attachment"
-	"market/internal/domain/product"
-	"market/internal/domain/product_market"
-	"market/internal/domain/user"
-	"market/internal/routes"
-	"market/pkg/cloud"
+	"fmt"
 	"market/pkg/config"
-	"market/pkg/database"
-	"market/pkg/logger"
+	"market/pkg/providers"
 	"market/pkg/providers/muffato"
-	"net/http"
+	"market/pkg/providers/vtex"
+	"os"
+
+	"go.uber.org/zap"
 
 	_ "market/docs"
 )
 
-func main() {
+// Exit codes shared by every command so cron jobs and containers can react to them
+const (
+	exitOK         = 0
+	exitFailure    = 1
+	exitUsage      = 2
+	exitIncomplete = 3
+)
 
-	// Load environment configuration
-	config.Load()
+const usage = `usage: market <command> [flags]
 
-	// Initialize logger
-	log := logger.NewLogger()
+commands:
+  serve     start the HTTP API (default when no command is given)
+  sync      run a provider ingestion once and print the report
+  migrate   manage the database schema
+  seed      load fixture categories, markets and category mappings
+  role      set the role of a user, e.g. to create the first admin
 
-	// Initialize client database connection
-	db := database.GetInstance(log)
-	defer db.Close()
+run "market <command> -h" for the flags of each command
+`
 
-	cloud.NewCloudInstance(cloud.AWS_PROVIDER)
+func main() {
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
 
-	// Initialize routes with handlers
-	routeInstance := routes.NewRoutes(
-		user.NewHandler(user.NewService(log)),
-		product.NewHandler(product.NewService(log)),
-		product_market.NewHandler(product_market.NewService(log)),
-		attachment.NewHandler(attachment.NewService(log)),
-	)
+	switch command {
+	case "serve":
+		os.Exit(runServe(args))
+	case "sync":
+		os.Exit(runSync(args))
+	case "migrate":
+		os.Exit(runMigrate(args))
+	case "seed":
+		os.Exit(runSeed(args))
+	case "role":
+		os.Exit(runRole(args))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		os.Exit(exitOK)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(exitUsage)
+	}
+}
 
-	muf := muffato.NewMuffatoProvider(log)
-	muf.FetchProducts()
+// newProviderRegistry registers Muffato plus every VTEX store listed in VTEX_STORES_FILE
+func newProviderRegistry(log *zap.SugaredLogger) (*providers.Registry, error) {
+	registry := providers.NewRegistry()
 
-	log.Infof("🙏 Starting server on port %s 🙏", config.Get().SERVER_PORT)
-	err := http.ListenAndServe(
-		config.Get().SERVER_PORT,
-		routeInstance,
-	)
+	muf, err := muffato.NewMuffatoProvider(log)
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.Register(muf); err != nil {
+		return nil, err
+	}
+
+	if config.Get().VTEX_STORES_FILE == "" {
+		return registry, nil
+	}
 
+	stores, err := vtex.LoadConfigs(config.Get().VTEX_STORES_FILE)
 	if err != nil {
-		log.Errorf("❌ failed to start server: %v", err)
-		panic(err)
+		return nil, err
 	}
+
+	for _, store := range stores {
+		provider, err := vtex.NewProvider(log, store)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
 }
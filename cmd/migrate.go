@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/logger"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = `usage: market migrate <command> [flags]
+
+commands:
+  up                      apply every pending migration
+  down [-steps N]         revert the last N applied migrations (default 1)
+  status                  list migrations and whether they are applied
+  create [-dir D] <name>  create an empty up/down migration pair
+`
+
+// runMigrate handles `market migrate ...` and returns the process exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return exitUsage
+	}
+
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		fs := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := fs.String("dir", database.MigrationsDir, "directory where the migration files are written")
+		if err := fs.Parse(args); err != nil {
+			return exitUsage
+		}
+		if fs.NArg() != 1 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			return exitUsage
+		}
+
+		up, down, err := database.CreateMigration(*dir, fs.Arg(0), time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitFailure
+		}
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+		return exitOK
+	}
+
+	config.Load()
+	log := logger.NewLogger()
+	defer log.Sync()
+
+	db := database.GetInstance(log)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, log)
+	if err != nil {
+		log.Errorf("❌ failed to load migrations: %v", err)
+		return exitFailure
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Errorf("❌ migrate up failed: %v", err)
+			return exitFailure
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+		if err := fs.Parse(args); err != nil || *steps < 1 {
+			return exitUsage
+		}
+
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Errorf("❌ migrate down failed: %v", err)
+			return exitFailure
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Errorf("❌ migrate status failed: %v", err)
+			return exitFailure
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		pending := false
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.Modified {
+				state = "modified"
+			}
+			if status.Missing {
+				state = "missing"
+			}
+			if !status.Applied || status.Modified || status.Missing {
+				pending = true
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		w.Flush()
+
+		// Non-zero exit lets deploy scripts detect a schema that is not up to date
+		if pending {
+			return exitIncomplete
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return exitUsage
+	}
+
+	return exitOK
+}
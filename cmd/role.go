@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"market/internal/domain/user"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/logger"
+	"os"
+)
+
+// runRole sets the role of a user by email, used to bootstrap the first admin
+// since role changes through the API already require one
+func runRole(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: market role <email> <admin|curator|member>")
+		return exitUsage
+	}
+
+	email, role := args[0], user.Role(args[1])
+	if !role.IsValid() {
+		fmt.Fprintln(os.Stderr, user.ErrInvalidRole)
+		return exitUsage
+	}
+
+	config.Load()
+	log := logger.NewLogger()
+	defer log.Sync()
+
+	db := database.GetInstance(log)
+	defer db.Close()
+
+	ctx := context.Background()
+	service := user.NewService(log)
+	users := user.NewRepository(log)
+
+	found, err := users.FindByEmail(ctx, email)
+	if err != nil {
+		log.Errorf("❌ failed to find user: %v", err)
+		return exitFailure
+	}
+	if found == nil {
+		fmt.Fprintf(os.Stderr, "user %s not found\n", email)
+		return exitFailure
+	}
+
+	if err := service.ChangeRole(ctx, found.ID, &user.RoleUpdateDTO{Role: role}); err != nil {
+		log.Errorf("❌ failed to change role: %v", err)
+		return exitFailure
+	}
+
+	fmt.Printf("%s is now %s\n", email, role)
+	return exitOK
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/logger"
+	"os"
+	"strings"
+)
+
+// runSeed loads the fixture scripts embedded from pkg/database/script. The
+// scripts are idempotent, so running it twice is harmless.
+func runSeed(args []string) int {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	only := fs.String("only", "", "comma separated seed names to load, e.g. categories,markets")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	seeds, err := database.EmbeddedSeeds()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitFailure
+	}
+
+	if *only != "" {
+		wanted := map[string]bool{}
+		for _, name := range strings.Split(*only, ",") {
+			wanted[strings.TrimSpace(name)] = true
+		}
+
+		selected := []database.Seed{}
+		for _, seed := range seeds {
+			if wanted[seed.Name] {
+				selected = append(selected, seed)
+				delete(wanted, seed.Name)
+			}
+		}
+		for name := range wanted {
+			fmt.Fprintf(os.Stderr, "unknown seed %q\n", name)
+			return exitUsage
+		}
+		seeds = selected
+	}
+
+	config.Load()
+	log := logger.NewLogger()
+	defer log.Sync()
+
+	db := database.GetInstance(log)
+	defer db.Close()
+
+	if err := db.RunSeeds(context.Background(), log, seeds); err != nil {
+		log.Errorf("❌ seed failed: %v", err)
+		return exitFailure
+	}
+
+	for _, seed := range seeds {
+		fmt.Printf("seeded %s\n", seed.Name)
+	}
+	return exitOK
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"market/internal/domain/api_key"
+	"market/internal/domain/attachment"
+	"market/internal/domain/basket"
+	"market/internal/domain/category_mapping"
+	"market/internal/domain/company"
+	"market/internal/domain/market"
+	"market/internal/domain/price_history"
+	"market/internal/domain/product"
+	"market/internal/domain/product_market"
+	"market/internal/domain/shopping_list"
+	"market/internal/domain/sync_run"
+	"market/internal/domain/user"
+	"market/internal/ingestion"
+	"market/internal/routes"
+	"market/internal/scheduler"
+	"market/pkg/cloud"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/health"
+	"market/pkg/httpx"
+	"market/pkg/logger"
+	"market/pkg/middleware"
+	"market/pkg/security"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runServe starts the HTTP API. Provider syncs only run in background when the
+// scheduler is enabled, otherwise they are triggered by `market sync` or the
+// admin endpoint.
+func runServe(args []string) int {
+	config.Load()
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", config.Get().SERVER_PORT, "address the API listens on")
+	withScheduler := fs.Bool("scheduler", config.Get().SYNC_SCHEDULER_ENABLED, "run provider syncs in background on SYNC_SCHEDULE")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	// Initialize logger
+	log := logger.NewLogger()
+	defer log.Sync()
+
+	// Initialize client database connection
+	db := database.GetInstance(log)
+	defer db.Close()
+
+	cloud.NewCloudInstance(cloud.AWS_PROVIDER)
+
+	if _, err := security.LoadKeyring(); err != nil {
+		log.Errorf("❌ failed to load JWT keys: %v", err)
+		return exitFailure
+	}
+
+	registry, err := newProviderRegistry(log)
+	if err != nil {
+		log.Errorf("❌ failed to load providers: %v", err)
+		return exitFailure
+	}
+
+	syncRuns := sync_run.NewService(log)
+	sched, err := scheduler.New(log, registry, ingestion.NewRunner(log), syncRuns)
+	if err != nil {
+		log.Errorf("❌ failed to create scheduler: %v", err)
+		return exitFailure
+	}
+	if *withScheduler {
+		sched.Start()
+	}
+
+	healthHandler := health.NewHandler(config.Get().HEALTH_CHECK_TIMEOUT)
+	healthHandler.Register("postgres", true, db.Ping)
+	healthHandler.Register("storage", false, func(ctx context.Context) error {
+		return cloud.Instance.Provider.CheckBucket(ctx, config.Get().CLOUD_BUCKET)
+	})
+	healthHandler.Register("provider_sync", false, health.MaxAge(config.Get().HEALTH_SYNC_MAX_AGE, syncRuns.LastSuccessAt))
+
+	httpx.MaxBodyBytes = int64(config.Get().SERVER_MAX_BODY_BYTES)
+	if config.Get().TRUST_PROXY_HEADERS && config.Get().TRUSTED_PROXY_HOPS <= 0 {
+		log.Warn("TRUST_PROXY_HEADERS is set without TRUSTED_PROXY_HOPS, proxy headers are ignored")
+	}
+
+	apiKeys := api_key.NewService(log)
+	middleware.UseAPIKeys(apiKeys.Authenticate)
+
+	// Initialize routes with handlers
+	routeInstance := routes.NewRoutes(
+		user.NewHandler(user.NewService(log)),
+		product.NewHandler(product.NewService(log)),
+		product_market.NewHandler(product_market.NewService(log)),
+		attachment.NewHandler(attachment.NewService(log)),
+		price_history.NewHandler(price_history.NewService(log)),
+		sync_run.NewHandler(syncRuns, sched),
+		category_mapping.NewHandler(category_mapping.NewService(log)),
+		market.NewHandler(market.NewService(log)),
+		basket.NewHandler(basket.NewService(log)),
+		shopping_list.NewHandler(shopping_list.NewService(log)),
+		company.NewHandler(company.NewService(log)),
+		api_key.NewHandler(apiKeys),
+		db,
+		healthHandler,
+	)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           routeInstance,
+		ReadTimeout:       config.Get().SERVER_READ_TIMEOUT,
+		ReadHeaderTimeout: config.Get().SERVER_READ_HEADER_TIMEOUT,
+		WriteTimeout:      config.Get().SERVER_WRITE_TIMEOUT,
+		IdleTimeout:       config.Get().SERVER_IDLE_TIMEOUT,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Infof("🙏 Starting server on port %s 🙏", *addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	code := exitOK
+	select {
+	case err := <-serverErr:
+		log.Errorf("❌ failed to start server: %v", err)
+		code = exitFailure
+	case <-ctx.Done():
+		log.Info("shutdown signal received, draining requests")
+	}
+
+	// A second signal during the drain kills the process right away
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Get().SERVER_SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("❌ failed to drain requests: %v", err)
+		code = exitFailure
+	}
+
+	if err := sched.Stop(shutdownCtx); err != nil {
+		log.Errorf("❌ sync jobs still running at shutdown: %v", err)
+		code = exitFailure
+	}
+
+	// Deferred db.Close and log.Sync run after this point
+	log.Info("server stopped")
+	return code
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"market/internal/domain/sync_run"
+	"market/internal/ingestion"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/logger"
+	"market/pkg/providers"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runSync runs the ingestion of one or every provider once, meant for cron
+// jobs and backfills. Exits with exitIncomplete when some offers failed.
+func runSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	name := fs.String("provider", "", "name of the provider to sync")
+	all := fs.Bool("all", false, "sync every registered provider")
+	dryRun := fs.Bool("dry-run", false, "fetch and map the catalog without writing to the database")
+	asJSON := fs.Bool("json", false, "print the reports as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if (*name == "" && !*all) || (*name != "" && *all) {
+		fmt.Fprintln(os.Stderr, "usage: market sync (-provider <name> | -all) [-dry-run] [-json]")
+		return exitUsage
+	}
+
+	config.Load()
+	log := logger.NewLogger()
+	defer log.Sync()
+
+	db := database.GetInstance(log)
+	defer db.Close()
+
+	registry, err := newProviderRegistry(log)
+	if err != nil {
+		log.Errorf("❌ failed to load providers: %v", err)
+		return exitFailure
+	}
+
+	selected := registry.All()
+	if !*all {
+		provider, ok := registry.Get(*name)
+		if !ok {
+			names := []string{}
+			for _, provider := range selected {
+				names = append(names, provider.Name())
+			}
+			fmt.Fprintf(os.Stderr, "unknown provider %q, available: %s\n", *name, strings.Join(names, ", "))
+			return exitUsage
+		}
+		selected = []providers.Provider{provider}
+	}
+
+	// Ctrl+C stops the crawl between pages and still records the partial run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := ingestion.NewRunner(log)
+	runner.DryRun = *dryRun
+	runs := sync_run.NewService(log)
+
+	code := exitOK
+	reports := []*ingestion.Report{}
+	for _, provider := range selected {
+		var run *sync_run.SyncRun
+		release := func() {}
+		if !*dryRun {
+			// Skip providers the scheduler or another sync is already running
+			release, err = ingestion.LockProvider(ctx, db, provider.Name())
+			if err != nil {
+				log.Errorw("provider sync skipped", "provider", provider.Name(), "error", err)
+				code = exitFailure
+				continue
+			}
+
+			run, err = runs.Start(ctx, provider.Name(), sync_run.SyncRunTriggerCLI)
+			if err != nil {
+				release()
+				log.Errorw("error recording sync run", "provider", provider.Name(), "error", err)
+				return exitFailure
+			}
+		}
+
+		report, err := runner.Run(ctx, provider)
+		if report != nil {
+			reports = append(reports, report)
+			if report.Failed > 0 && code == exitOK {
+				code = exitIncomplete
+			}
+		}
+		if err != nil {
+			log.Errorw("provider sync failed", "provider", provider.Name(), "error", err)
+			code = exitFailure
+		}
+
+		if run != nil {
+			if report != nil {
+				run.PagesFetched = report.Pages
+				run.ProductsFetched = report.Fetched
+				run.ProductsUpserted = report.Saved
+				run.Errors = report.Failed
+				run.UnmappedCategories = report.Unmapped
+			}
+			run.Finish(err)
+			if err := runs.Finish(context.WithoutCancel(ctx), run); err != nil {
+				log.Errorw("error recording sync run", "provider", provider.Name(), "run_id", run.ID, "error", err)
+			}
+		}
+		release()
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return exitFailure
+		}
+		return code
+	}
+
+	for _, report := range reports {
+		fmt.Printf(
+			"%s: pages=%d fetched=%d saved=%d failed=%d unmapped=%d dry_run=%t duration=%s\n",
+			report.Provider, report.Pages, report.Fetched, report.Saved, report.Failed,
+			len(report.Unmapped), report.DryRun, report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond),
+		)
+		for _, category := range report.Unmapped {
+			fmt.Printf("  unmapped category: %s\n", category)
+		}
+	}
+
+	return code
+}
@@ -0,0 +1,18 @@
+package api_key
+
+import (
+	"market/pkg/security"
+	"time"
+)
+
+type APIKeyCreateDTO struct {
+	Name      string           `json:"name" validate:"required,max=100"`
+	Scopes    []security.Scope `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time       `json:"expires_at,omitempty"`
+}
+
+// APIKeyCreatedDTO is the only response carrying the key
+type APIKeyCreatedDTO struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,75 @@
+package api_key
+
+import (
+	"market/internal/domain/user"
+	"market/pkg/security"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyPrefix starts every API key, so the middleware tells them apart from JWTs
+const KeyPrefix = "mk_"
+
+// displayLength is how much of the key is kept in clear to identify it
+const displayLength = len(KeyPrefix) + 8
+
+// APIKey lets scripts and partner systems call the API on behalf of a
+// company. Only the hash of the key is stored, the key itself is shown once.
+type APIKey struct {
+	ID         uuid.UUID        `json:"id"`
+	CompanyID  uuid.UUID        `json:"company_id"`
+	CreatedBy  uuid.UUID        `json:"created_by"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	KeyHash    string           `json:"-"`
+	Scopes     []security.Scope `json:"scopes"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// NewKey generates a key and returns it with its clear prefix
+func NewKey() (key, prefix string) {
+	key = KeyPrefix + security.NewOpaqueToken()
+	return key, key[:displayLength]
+}
+
+// IsKey tells whether the bearer token looks like an API key
+func IsKey(token string) bool {
+	return strings.HasPrefix(token, KeyPrefix)
+}
+
+// Active tells whether the key can still be used
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// scopeRoles is the role a person needs to grant a scope. Keys act with the
+// current role of their creator, so a scope also stops working when the
+// creator loses the role.
+var scopeRoles = map[security.Scope]user.Role{
+	security.ScopeCatalogRead:  user.RoleMember,
+	security.ScopeCatalogWrite: user.RoleCurator,
+	security.ScopePricesRead:   user.RoleMember,
+	security.ScopePricesWrite:  user.RoleCurator,
+	security.ScopeSyncRun:      user.RoleAdmin,
+}
+
+// RoleAllows tells whether a person with the role may use the scope
+func RoleAllows(role user.Role, scope security.Scope) bool {
+	required, ok := scopeRoles[scope]
+	if !ok {
+		return false
+	}
+	switch required {
+	case user.RoleMember:
+		return role.IsValid()
+	case user.RoleCurator:
+		return role == user.RoleCurator || role == user.RoleAdmin
+	default:
+		return role == required
+	}
+}
@@ -0,0 +1,30 @@
+package api_key
+
+import (
+	"market/internal/domain/user"
+	"market/pkg/security"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role  user.Role
+		scope security.Scope
+		want  bool
+	}{
+		{user.RoleMember, security.ScopeCatalogRead, true},
+		{user.RoleMember, security.ScopePricesWrite, false},
+		{user.RoleCurator, security.ScopeCatalogWrite, true},
+		{user.RoleCurator, security.ScopeSyncRun, false},
+		{user.RoleAdmin, security.ScopeCatalogWrite, true},
+		{user.RoleAdmin, security.ScopeSyncRun, true},
+		{"", security.ScopeCatalogRead, false},
+		{user.RoleAdmin, "unknown:scope", false},
+	}
+
+	for _, c := range cases {
+		if got := RoleAllows(c.role, c.scope); got != c.want {
+			t.Errorf("RoleAllows(%q, %q) = %t, want %t", c.role, c.scope, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api_key
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// CreateAPIKeyHandler godoc
+// @Summary      Criar chave de API
+// @Description  Cria uma chave de API para a empresa ativa. A chave é exibida apenas nesta resposta. Só é possível conceder os escopos permitidos ao seu papel.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		APIKeyCreateDTO		true	"Nome, escopos e validade"
+// @Success      201		{object}	APIKeyCreatedDTO
+// @Failure      400		{object}	httpx.Problem
+// @Failure      403		{object}	httpx.Problem
+// @Router       /api-keys [post]
+func (h *Handler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var dto APIKeyCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	created, err := h.usecase.Create(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, created)
+}
+
+// ListAPIKeysHandler godoc
+// @Summary      Listar chaves de API
+// @Tags         api-keys
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200	{array}		APIKey
+// @Router       /api-keys [get]
+func (h *Handler) ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.usecase.List(r.Context())
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, keys)
+}
+
+// RevokeAPIKeyHandler godoc
+// @Summary      Revogar chave de API
+// @Tags         api-keys
+// @Security     ApiKeyAuth
+// @Param        id	path	string	true	"API key ID"
+// @Success      204
+// @Failure      404	{object}	httpx.Problem
+// @Router       /api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid API key ID")
+		return
+	}
+
+	if err := h.usecase.Revoke(r.Context(), id); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,184 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"market/pkg/security"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Save(ctx context.Context, key *APIKey) error
+	// List returns the keys of the caller company
+	List(ctx context.Context) ([]*APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+	// Revoke revokes a key of the caller company, returning false when there is none
+	Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+	// Touch records the key usage, at most once a minute
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type repository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+const keyColumns = `id, company_id, created_by, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO api_keys
+		(id, company_id, created_by, name, prefix, key_hash, scopes, expires_at, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+	RETURNING created_at`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &repository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, key *APIKey) error {
+	err := r.db.Stmt(ctx, r.createStatement).QueryRowContext(
+		ctx,
+		key.ID,
+		key.CompanyID,
+		key.CreatedBy,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+	).Scan(&key.CreatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute Save", "error", err, "company_id", key.CompanyID)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) List(ctx context.Context) ([]*APIKey, error) {
+	sql := `SELECT ` + keyColumns + ` FROM api_keys
+	WHERE company_id = $1
+	ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, sql, security.CompanyID(ctx))
+	if err != nil {
+		r.log.Errorw("error on execute List", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*APIKey{}
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			r.log.Errorw("error on scan List", "error", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error on iterate List", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *repository) FindByHash(ctx context.Context, hash string) (*APIKey, error) {
+	sql := `SELECT ` + keyColumns + ` FROM api_keys WHERE key_hash = $1 LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, hash)
+	if err != nil {
+		r.log.Errorw("error on execute FindByHash", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	key, err := scanKey(rows)
+	if err != nil {
+		r.log.Errorw("error on scan FindByHash", "error", err)
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *repository) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	sql := `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+	WHERE id = $1 AND company_id = $2`
+
+	result, err := r.db.ExecContext(ctx, sql, id, security.CompanyID(ctx))
+	if err != nil {
+		r.log.Errorw("error on execute Revoke", "error", err, "id", id)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *repository) Touch(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')`
+
+	if _, err := r.db.ExecContext(ctx, sql, id); err != nil {
+		r.log.Errorw("error on execute Touch", "error", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func scanKey(rows *sql.Rows) (*APIKey, error) {
+	var (
+		key    APIKey
+		scopes pq.StringArray
+	)
+	err := rows.Scan(
+		&key.ID,
+		&key.CompanyID,
+		&key.CreatedBy,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]security.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, security.Scope(scope))
+	}
+	return &key, nil
+}
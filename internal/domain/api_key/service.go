@@ -0,0 +1,173 @@
+package api_key
+
+import (
+	"context"
+	"fmt"
+	"market/internal/domain/company"
+	"market/internal/domain/user"
+	"market/pkg/apperr"
+	"market/pkg/security"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAPIKeyNotFound = apperr.NotFound("api_key_not_found", "api key not found")
+	ErrInvalidAPIKey  = apperr.Unauthorized("invalid_api_key", "api key is invalid, expired or revoked")
+	ErrNameRequired   = apperr.Validation("name_required", "name is required")
+	ErrScopesRequired = apperr.Validation("scopes_required", "at least one scope is required")
+	ErrInvalidScope   = apperr.Validation("invalid_scope", "invalid scope")
+	ErrInvalidExpiry  = apperr.Validation("invalid_expiry", "expires_at must be in the future")
+	ErrKeyCreatesKey  = apperr.Forbidden("api_key_manages_api_keys", "api keys cannot manage api keys")
+	ErrScopeForbidden = apperr.Forbidden("scope_not_allowed", "your role cannot grant this scope")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, input *APIKeyCreateDTO) (*APIKeyCreatedDTO, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+
+	// Authenticate resolves a key into the identity used by the auth middleware
+	Authenticate(ctx context.Context, key string) (*security.UserAuth, error)
+}
+
+type service struct {
+	log               *zap.SugaredLogger
+	repository        Repository
+	userRepository    user.Repository
+	companyRepository company.Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:               log,
+		repository:        NewRepository(log),
+		userRepository:    user.NewRepository(log),
+		companyRepository: company.NewRepository(log),
+	}
+}
+
+// Create generates a key for the caller company. The key is only returned here.
+// Callers can only grant the scopes their own role allows.
+func (s *service) Create(ctx context.Context, input *APIKeyCreateDTO) (*APIKeyCreatedDTO, error) {
+	userAuth, err := security.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userAuth.IsAPIKey() {
+		return nil, ErrKeyCreatesKey
+	}
+
+	if input == nil || strings.TrimSpace(input.Name) == "" {
+		return nil, ErrNameRequired
+	}
+	if len(input.Scopes) == 0 {
+		return nil, ErrScopesRequired
+	}
+	for _, scope := range input.Scopes {
+		if !scope.IsValid() {
+			return nil, ErrInvalidScope.WithFields(apperr.Field("scopes", fmt.Sprintf("unknown scope %q", scope)))
+		}
+		if !RoleAllows(user.Role(userAuth.Role), scope) {
+			return nil, ErrScopeForbidden.WithFields(apperr.Field("scopes", fmt.Sprintf("%s cannot grant %q", userAuth.Role, scope)))
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	key, prefix := NewKey()
+	apiKey := &APIKey{
+		ID:        uuid.New(),
+		CompanyID: userAuth.CompanyID,
+		CreatedBy: userAuth.UserID,
+		Name:      strings.TrimSpace(input.Name),
+		Prefix:    prefix,
+		KeyHash:   security.HashToken(key),
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	if err := s.repository.Save(ctx, apiKey); err != nil {
+		s.log.Errorw("error creating api key", "error", err)
+		return nil, err
+	}
+
+	s.log.Infow("api key created", "id", apiKey.ID, "company_id", apiKey.CompanyID, "scopes", apiKey.Scopes)
+	return &APIKeyCreatedDTO{APIKey: apiKey, Key: key}, nil
+}
+
+func (s *service) List(ctx context.Context) ([]*APIKey, error) {
+	return s.repository.List(ctx)
+}
+
+func (s *service) Revoke(ctx context.Context, id uuid.UUID) error {
+	revoked, err := s.repository.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	s.log.Infow("api key revoked", "id", id)
+	return nil
+}
+
+// Authenticate resolves the key with the current standing of its creator: the
+// key stops working when the creator is disabled or leaves the company, and
+// keeps only the scopes the creator role still allows.
+func (s *service) Authenticate(ctx context.Context, key string) (*security.UserAuth, error) {
+	if !IsKey(key) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repository.FindByHash(ctx, security.HashToken(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || !apiKey.Active(time.Now()) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	creator, err := s.userRepository.FindByID(ctx, apiKey.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if creator == nil || creator.Status != user.UserStatusActive {
+		return nil, ErrInvalidAPIKey
+	}
+
+	member, err := s.companyRepository.IsMember(ctx, apiKey.CompanyID, creator.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrInvalidAPIKey
+	}
+
+	scopes := make([]security.Scope, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		if RoleAllows(creator.Role, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	// A failed usage update should not refuse the request
+	if err := s.repository.Touch(ctx, apiKey.ID); err != nil {
+		s.log.Warnw("error recording api key usage", "error", err, "id", apiKey.ID)
+	}
+
+	return &security.UserAuth{
+		UserID:    apiKey.CreatedBy,
+		CompanyID: apiKey.CompanyID,
+		Role:      string(creator.Role),
+		APIKeyID:  apiKey.ID,
+		Scopes:    scopes,
+	}, nil
+}
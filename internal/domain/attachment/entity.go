@@ -8,6 +8,7 @@ import (
 
 type Attachment struct {
 	ID          uuid.UUID `json:"id" db:"id"`
+	CompanyID   uuid.UUID `json:"company_id" db:"company_id"`
 	URL         string    `json:"url" db:"url"`
 	Type        *string   `json:"type" db:"type"`
 	Description *string   `json:"description" db:"description"`
@@ -15,9 +16,10 @@ type Attachment struct {
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
-func NewAttachment(url string, attachmentType, description *string) *Attachment {
+func NewAttachment(companyID uuid.UUID, url string, attachmentType, description *string) *Attachment {
 	return &Attachment{
 		ID:          uuid.New(),
+		CompanyID:   companyID,
 		URL:         url,
 		Type:        attachmentType,
 		Description: description,
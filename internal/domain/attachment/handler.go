@@ -1,11 +1,12 @@
 package attachment
 
 import (
-	"encoding/json"
 	"io"
 	"market/pkg/cloud"
 	"market/pkg/config"
 	"market/pkg/httpx"
+	"market/pkg/query"
+	"market/pkg/validate"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -30,9 +31,9 @@ func NewHandler(usecase UseCase) *Handler {
 // @Param file formData file true "Image file to upload (JPG, PNG, GIF, WebP)"
 // @Param description formData string false "Image description"
 // @Success 201 {object} AttachmentFoundDTO
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} httpx.Problem
+// @Failure 401 {object} httpx.Problem
+// @Failure 500 {object} httpx.Problem
 // @Security ApiKeyAuth
 // @Router /attachments [post]
 func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
@@ -63,7 +64,7 @@ func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
 	// Read file content
 	fileContent, err := io.ReadAll(file)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to read file", err)
+		httpx.SendInternalServerError(w, "Failed to read file")
 		return
 	}
 
@@ -83,7 +84,7 @@ func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
 	bucketName := config.Get().CLOUD_BUCKET
 	fileURL, err := cloud.Instance.Provider.UploadFile(fileContent, bucketName, contentType)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to upload file", err)
+		httpx.SendError(w, ErrUploadFailed.Wrap(err))
 		return
 	}
 
@@ -102,16 +103,55 @@ func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
 		Type:        &imageType,
 		Description: description,
 	}
+	if err := validate.Struct(createDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
 
-	attachment, err := h.usecase.Create(createDTO)
+	attachment, err := h.usecase.Create(r.Context(), createDTO)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to create attachment", err)
+		httpx.SendError(w, err)
 		return
 	}
 
 	httpx.SendCreated(w, attachment)
 }
 
+// ListAttachments godoc
+// @Summary List attachments
+// @Description List the attachments of the company with page or cursor pagination, filters and sorting
+// @Tags attachments
+// @Produce json
+// @Param type query string false "Attachment type"
+// @Param created_at_gte query string false "Created from (YYYY-MM-DD or RFC 3339)"
+// @Param created_at_lte query string false "Created until (YYYY-MM-DD or RFC 3339)"
+// @Param sort query string false "Sort fields, - for descending (created_at)"
+// @Param page query int false "Page (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} query.Page[AttachmentFoundDTO]
+// @Failure 400 {object} httpx.Problem
+// @Failure 500 {object} httpx.Problem
+// @Security ApiKeyAuth
+// @Router /attachments [get]
+func (h *Handler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params, err := query.Parse(r.URL.Query(), attachmentQuery)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	attachments, err := h.usecase.List(r.Context(), params)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, attachments)
+}
+
 // GetAttachmentByID godoc
 // @Summary Get attachment by ID
 // @Description Retrieve a specific attachment by its ID
@@ -119,9 +159,9 @@ func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Attachment ID"
 // @Success 200 {object} AttachmentFoundDTO
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} httpx.Problem
+// @Failure 404 {object} httpx.Problem
+// @Failure 500 {object} httpx.Problem
 // @Router /attachments/{id} [get]
 func (h *Handler) GetAttachmentByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -140,14 +180,9 @@ func (h *Handler) GetAttachmentByID(w http.ResponseWriter, r *http.Request) {
 	// 	return
 	// }
 
-	attachment, err := h.usecase.FindByID(id)
+	attachment, err := h.usecase.FindByID(r.Context(), id)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to get attachment", err)
-		return
-	}
-
-	if attachment == nil {
-		httpx.SendNotFound(w, "Attachment not found")
+		httpx.SendError(w, err)
 		return
 	}
 
@@ -163,9 +198,9 @@ func (h *Handler) GetAttachmentByID(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Attachment ID"
 // @Param attachment body AttachmentUpdateDTO true "Attachment data to update"
 // @Success 200 {object} AttachmentFoundDTO
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} httpx.Problem
+// @Failure 404 {object} httpx.Problem
+// @Failure 500 {object} httpx.Problem
 // @Router /attachments/{id} [put]
 func (h *Handler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -179,8 +214,8 @@ func (h *Handler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var updateDTO AttachmentUpdateDTO
-	if err := json.NewDecoder(r.Body).Decode(&updateDTO); err != nil {
-		httpx.SendBadRequest(w, "Invalid JSON format")
+	if err := httpx.DecodeJSON(w, r, &updateDTO); err != nil {
+		httpx.SendError(w, err)
 		return
 	}
 
@@ -190,14 +225,9 @@ func (h *Handler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
 	// 	return
 	// }
 
-	attachment, err := h.usecase.Update(id, &updateDTO)
+	attachment, err := h.usecase.Update(r.Context(), id, &updateDTO)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to update attachment", err)
-		return
-	}
-
-	if attachment == nil {
-		httpx.SendNotFound(w, "Attachment not found")
+		httpx.SendError(w, err)
 		return
 	}
 
@@ -211,9 +241,9 @@ func (h *Handler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Attachment ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} httpx.Problem
+// @Failure 404 {object} httpx.Problem
+// @Failure 500 {object} httpx.Problem
 // @Router /attachments/{id} [delete]
 func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -232,9 +262,9 @@ func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
 	// 	return
 	// }
 
-	err = h.usecase.Delete(id)
+	err = h.usecase.Delete(r.Context(), id)
 	if err != nil {
-		httpx.SendInternalServerError(w, "Failed to delete attachment", err)
+		httpx.SendError(w, err)
 		return
 	}
 
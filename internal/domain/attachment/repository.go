@@ -1,18 +1,22 @@
 package attachment
 
 import (
+	"context"
 	"database/sql"
 	"market/pkg/database"
+	"market/pkg/query"
+	"market/pkg/security"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
-	Create(attachment *Attachment) (*Attachment, error)
-	FindByID(id uuid.UUID) (*Attachment, error)
-	Update(id uuid.UUID, attachment *Attachment) (*Attachment, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, attachment *Attachment) (*Attachment, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	List(ctx context.Context, params *query.Params) ([]*Attachment, int, error)
+	Update(ctx context.Context, id uuid.UUID, attachment *Attachment) (*Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type repository struct {
@@ -21,6 +25,27 @@ type repository struct {
 	createStatement *sql.Stmt
 }
 
+// attachmentQuery is what clients may filter and sort attachment listings by
+var attachmentQuery = &query.Schema{
+	Fields: map[string]query.Field{
+		"id":         {Column: "id", Type: query.UUID},
+		"type":       {Column: "type", Type: query.String, Ops: []query.Op{query.Eq}},
+		"created_at": {Column: "created_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: []query.Sort{{Field: "created_at", Desc: true}},
+	DefaultSize: 20,
+	MaxSize:     100,
+}
+
+// attachmentSortValue feeds the next cursor of attachment listings
+func attachmentSortValue(attachment *Attachment, field string) any {
+	if field == "created_at" {
+		return attachment.CreatedAt
+	}
+	return attachment.ID
+}
+
 func NewRepository(
 	log *zap.SugaredLogger,
 ) Repository {
@@ -28,9 +53,9 @@ func NewRepository(
 	dbInstance := database.GetInstance(log)
 
 	insert := `INSERT INTO public.attachments
-		(id, url, type, description, created_at, updated_at)
+		(id, company_id, url, type, description, created_at, updated_at)
 	VALUES
-		($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
 
 	createStatement, err := dbInstance.Prepare(insert)
 	if err != nil {
@@ -45,9 +70,11 @@ func NewRepository(
 	}
 }
 
-func (o *repository) Create(attachment *Attachment) (*Attachment, error) {
-	_, err := o.createStatement.Exec(
+func (o *repository) Create(ctx context.Context, attachment *Attachment) (*Attachment, error) {
+	_, err := o.db.Stmt(ctx, o.createStatement).ExecContext(
+		ctx,
 		attachment.ID,
+		attachment.CompanyID,
 		attachment.URL,
 		attachment.Type,
 		attachment.Description,
@@ -61,10 +88,10 @@ func (o *repository) Create(attachment *Attachment) (*Attachment, error) {
 	return attachment, nil
 }
 
-func (o *repository) FindByID(id uuid.UUID) (*Attachment, error) {
-	sql := `SELECT id, url, type, description, created_at, updated_at
-	FROM attachments WHERE id = $1 LIMIT 1`
-	row, err := o.db.Query(sql, id)
+func (o *repository) FindByID(ctx context.Context, id uuid.UUID) (*Attachment, error) {
+	sql := `SELECT id, company_id, url, type, description, created_at, updated_at
+	FROM attachments WHERE id = $1 AND company_id = $2 LIMIT 1`
+	row, err := o.db.QueryContext(ctx, sql, id, security.CompanyID(ctx))
 
 	if err != nil {
 		o.log.Errorw("error on execute FindByID", "error", err)
@@ -77,6 +104,7 @@ func (o *repository) FindByID(id uuid.UUID) (*Attachment, error) {
 	if row.Next() {
 		err = row.Scan(
 			&attachment.ID,
+			&attachment.CompanyID,
 			&attachment.URL,
 			&attachment.Type,
 			&attachment.Description,
@@ -93,24 +121,72 @@ func (o *repository) FindByID(id uuid.UUID) (*Attachment, error) {
 	return nil, nil
 }
 
-func (o *repository) Update(id uuid.UUID, attachment *Attachment) (*Attachment, error) {
+func (o *repository) List(ctx context.Context, params *query.Params) ([]*Attachment, int, error) {
+	b := params.NewBuilder(security.CompanyID(ctx))
+	where := `WHERE company_id = $1` + b.Filters()
+
+	var total int
+	if err := o.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM attachments `+where, b.Args()...).Scan(&total); err != nil {
+		o.log.Errorw("error on count List", "error", err)
+		return nil, 0, err
+	}
+
+	sql := `SELECT id, company_id, url, type, description, created_at, updated_at
+	FROM attachments ` + where + b.Page()
+
+	rows, err := o.db.QueryContext(ctx, sql, b.Args()...)
+	if err != nil {
+		o.log.Errorw("error on execute List", "error", err)
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	attachments := []*Attachment{}
+	for rows.Next() {
+		var attachment Attachment
+		err = rows.Scan(
+			&attachment.ID,
+			&attachment.CompanyID,
+			&attachment.URL,
+			&attachment.Type,
+			&attachment.Description,
+			&attachment.CreatedAt,
+			&attachment.UpdatedAt,
+		)
+		if err != nil {
+			o.log.Errorw("error on scan List", "error", err)
+			return nil, 0, err
+		}
+		attachments = append(attachments, &attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		o.log.Errorw("error on iterate List", "error", err)
+		return nil, 0, err
+	}
+
+	return attachments, total, nil
+}
+
+func (o *repository) Update(ctx context.Context, id uuid.UUID, attachment *Attachment) (*Attachment, error) {
 	sql := `UPDATE attachments SET 
 		url = $2, type = $3, description = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1`
+		WHERE id = $1 AND company_id = $5`
 
-	_, err := o.db.Exec(sql, id, attachment.URL, attachment.Type, attachment.Description)
+	_, err := o.db.ExecContext(ctx, sql, id, attachment.URL, attachment.Type, attachment.Description, security.CompanyID(ctx))
 	if err != nil {
 		o.log.Errorw("error on execute Update", "error", err)
 		return nil, err
 	}
 
-	return o.FindByID(id)
+	return o.FindByID(ctx, id)
 }
 
-func (o *repository) Delete(id uuid.UUID) error {
-	sql := `DELETE FROM attachments WHERE id = $1`
+func (o *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `DELETE FROM attachments WHERE id = $1 AND company_id = $2`
 
-	_, err := o.db.Exec(sql, id)
+	_, err := o.db.ExecContext(ctx, sql, id, security.CompanyID(ctx))
 	if err != nil {
 		o.log.Errorw("error on execute Delete", "error", err)
 		return err
@@ -1,15 +1,26 @@
 package attachment
 
 import (
+	"context"
+	"market/pkg/apperr"
+	"market/pkg/query"
+	"market/pkg/security"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrAttachmentNotFound = apperr.NotFound("attachment_not_found", "attachment not found")
+	ErrUploadFailed       = apperr.Upstream("upload_failed", "failed to upload file to storage")
+)
+
 type UseCase interface {
-	Create(input *AttachmentCreateDTO) (*AttachmentFoundDTO, error)
-	FindByID(id uuid.UUID) (*AttachmentFoundDTO, error)
-	Update(id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentFoundDTO, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, input *AttachmentCreateDTO) (*AttachmentFoundDTO, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*AttachmentFoundDTO, error)
+	List(ctx context.Context, params *query.Params) (*query.Page[*AttachmentFoundDTO], error)
+	Update(ctx context.Context, id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentFoundDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type service struct {
@@ -26,14 +37,15 @@ func NewService(
 	}
 }
 
-func (s *service) Create(input *AttachmentCreateDTO) (*AttachmentFoundDTO, error) {
+func (s *service) Create(ctx context.Context, input *AttachmentCreateDTO) (*AttachmentFoundDTO, error) {
 	attachment := NewAttachment(
+		security.CompanyID(ctx),
 		input.URL,
 		input.Type,
 		input.Description,
 	)
 
-	createdAttachment, err := s.repository.Create(attachment)
+	createdAttachment, err := s.repository.Create(ctx, attachment)
 	if err != nil {
 		s.log.Errorw("error creating attachment", "error", err)
 		return nil, err
@@ -49,15 +61,15 @@ func (s *service) Create(input *AttachmentCreateDTO) (*AttachmentFoundDTO, error
 	}, nil
 }
 
-func (s *service) FindByID(id uuid.UUID) (*AttachmentFoundDTO, error) {
-	attachment, err := s.repository.FindByID(id)
+func (s *service) FindByID(ctx context.Context, id uuid.UUID) (*AttachmentFoundDTO, error) {
+	attachment, err := s.repository.FindByID(ctx, id)
 	if err != nil {
 		s.log.Errorw("error finding attachment by ID", "id", id, "error", err)
 		return nil, err
 	}
 	if attachment == nil {
 		s.log.Warnw("attachment not found", "id", id)
-		return nil, nil
+		return nil, ErrAttachmentNotFound
 	}
 	return &AttachmentFoundDTO{
 		ID:          attachment.ID,
@@ -69,16 +81,36 @@ func (s *service) FindByID(id uuid.UUID) (*AttachmentFoundDTO, error) {
 	}, nil
 }
 
-func (s *service) Update(id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentFoundDTO, error) {
+func (s *service) List(ctx context.Context, params *query.Params) (*query.Page[*AttachmentFoundDTO], error) {
+	attachments, total, err := s.repository.List(ctx, params)
+	if err != nil {
+		s.log.Errorw("error listing attachments", "error", err)
+		return nil, err
+	}
+
+	page := query.NewPage(attachments, total, params, attachmentSortValue)
+	return query.Map(page, func(attachment *Attachment) *AttachmentFoundDTO {
+		return &AttachmentFoundDTO{
+			ID:          attachment.ID,
+			URL:         attachment.URL,
+			Type:        attachment.Type,
+			Description: attachment.Description,
+			CreatedAt:   attachment.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:   attachment.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}), nil
+}
+
+func (s *service) Update(ctx context.Context, id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentFoundDTO, error) {
 	// First check if attachment exists
-	existingAttachment, err := s.repository.FindByID(id)
+	existingAttachment, err := s.repository.FindByID(ctx, id)
 	if err != nil {
 		s.log.Errorw("error finding attachment for update", "id", id, "error", err)
 		return nil, err
 	}
 	if existingAttachment == nil {
 		s.log.Warnw("attachment not found for update", "id", id)
-		return nil, nil
+		return nil, ErrAttachmentNotFound
 	}
 
 	// Update only provided fields
@@ -98,7 +130,7 @@ func (s *service) Update(id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentF
 		updateAttachment.Description = input.Description
 	}
 
-	updatedAttachment, err := s.repository.Update(id, updateAttachment)
+	updatedAttachment, err := s.repository.Update(ctx, id, updateAttachment)
 	if err != nil {
 		s.log.Errorw("error updating attachment", "id", id, "error", err)
 		return nil, err
@@ -114,19 +146,19 @@ func (s *service) Update(id uuid.UUID, input *AttachmentUpdateDTO) (*AttachmentF
 	}, nil
 }
 
-func (s *service) Delete(id uuid.UUID) error {
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 	// Check if attachment exists
-	existingAttachment, err := s.repository.FindByID(id)
+	existingAttachment, err := s.repository.FindByID(ctx, id)
 	if err != nil {
 		s.log.Errorw("error finding attachment for deletion", "id", id, "error", err)
 		return err
 	}
 	if existingAttachment == nil {
 		s.log.Warnw("attachment not found for deletion", "id", id)
-		return nil
+		return ErrAttachmentNotFound
 	}
 
-	err = s.repository.Delete(id)
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		s.log.Errorw("error deleting attachment", "id", id, "error", err)
 		return err
@@ -0,0 +1,46 @@
+package basket
+
+import (
+	"github.com/google/uuid"
+)
+
+type BasketItemDTO struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  float64   `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+}
+
+type BasketCompareDTO struct {
+	Items      []BasketItemDTO `json:"items" validate:"required,min=1,max=100,dive"`
+	MaxMarkets int             `json:"max_markets,omitempty" validate:"omitempty,min=1,max=4"`
+}
+
+type BasketLineDTO struct {
+	ProductID   uuid.UUID  `json:"product_id"`
+	StoreID     *uuid.UUID `json:"store_id,omitempty"`
+	Quantity    float64    `json:"quantity"`
+	UnitPrice   float64    `json:"unit_price"`
+	Subtotal    float64    `json:"subtotal"`
+	Promotional bool       `json:"promotional"`
+}
+
+type MarketTotalDTO struct {
+	MarketID   uuid.UUID       `json:"market_id"`
+	MarketName string          `json:"market_name"`
+	Total      float64         `json:"total"`
+	Complete   bool            `json:"complete"`
+	Items      []BasketLineDTO `json:"items"`
+	Missing    []uuid.UUID     `json:"missing"`
+}
+
+type SplitPlanDTO struct {
+	Markets []*MarketTotalDTO `json:"markets"`
+	Total   float64           `json:"total"`
+	Missing []uuid.UUID       `json:"missing"`
+	// Savings compared to the cheapest market that has every item, when there is one
+	Savings *float64 `json:"savings,omitempty"`
+}
+
+type BasketComparisonDTO struct {
+	Markets   []*MarketTotalDTO `json:"markets"`
+	SplitPlan *SplitPlanDTO     `json:"split_plan"`
+}
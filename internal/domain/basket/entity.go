@@ -0,0 +1,41 @@
+package basket
+
+import (
+	"github.com/google/uuid"
+)
+
+// Item is a product requested in the basket with the wanted quantity
+type Item struct {
+	ProductID uuid.UUID
+	Quantity  float64
+}
+
+// Offer is the cheapest price found for a product in a market
+type Offer struct {
+	ProductID   uuid.UUID
+	MarketID    uuid.UUID
+	StoreID     *uuid.UUID
+	UnitPrice   float64
+	Promotional bool
+}
+
+// priceTable indexes offers by product and then by market
+type priceTable map[uuid.UUID]map[uuid.UUID]Offer
+
+func (t priceTable) add(offer Offer) {
+	markets, ok := t[offer.ProductID]
+	if !ok {
+		markets = map[uuid.UUID]Offer{}
+		t[offer.ProductID] = markets
+	}
+
+	current, ok := markets[offer.MarketID]
+	if !ok || offer.UnitPrice < current.UnitPrice {
+		markets[offer.MarketID] = offer
+	}
+}
+
+func (t priceTable) offer(productID, marketID uuid.UUID) (Offer, bool) {
+	offer, ok := t[productID][marketID]
+	return offer, ok
+}
@@ -0,0 +1,45 @@
+package basket
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// CompareBasketHandler godoc
+// @Summary      Comparar cesta entre mercados
+// @Description  Calcula o total da cesta em cada mercado (usando o preço promocional quando houver), os itens faltantes e o melhor plano de compra dividido em até max_markets mercados
+// @Tags         baskets
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		BasketCompareDTO	true	"Basket items"
+// @Success      200		{object}	BasketComparisonDTO
+// @Failure      400		{object}	httpx.Problem
+// @Router       /baskets/compare [post]
+func (h *Handler) CompareBasketHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var dto BasketCompareDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	comparison, err := h.usecase.Compare(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, comparison)
+}
@@ -0,0 +1,197 @@
+package basket
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// maxCandidateMarkets bounds the combinations explored by the split plan
+const maxCandidateMarkets = 12
+
+// marketTotals prices the whole basket in every market that has at least one item,
+// complete markets first and then cheapest first
+func marketTotals(items []Item, table priceTable) []*MarketTotalDTO {
+	totals := []*MarketTotalDTO{}
+	for _, marketID := range marketsOf(table) {
+		totals = append(totals, priceIn(items, table, marketID))
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		return betterTotal(totals[i], totals[j])
+	})
+	return totals
+}
+
+// bestSplit finds the cheapest way to buy the basket using at most maxMarkets
+// markets. Coverage wins over price: a plan that finds more items is always
+// preferred, then the lower total, then fewer markets.
+func bestSplit(items []Item, table priceTable, maxMarkets int) *SplitPlanDTO {
+	singles := marketTotals(items, table)
+
+	candidates := make([]uuid.UUID, 0, len(singles))
+	for _, total := range singles {
+		candidates = append(candidates, total.MarketID)
+	}
+	if len(candidates) > maxCandidateMarkets {
+		candidates = candidates[:maxCandidateMarkets]
+	}
+
+	var best *SplitPlanDTO
+	var bestCovered int
+
+	combinations(candidates, maxMarkets, func(markets []uuid.UUID) {
+		plan, covered := planFor(items, table, markets)
+		if best == nil ||
+			covered > bestCovered ||
+			covered == bestCovered && plan.Total < best.Total-0.005 ||
+			covered == bestCovered && math.Abs(plan.Total-best.Total) < 0.005 && len(plan.Markets) < len(best.Markets) {
+			best = plan
+			bestCovered = covered
+		}
+	})
+
+	if best == nil {
+		best = &SplitPlanDTO{Markets: []*MarketTotalDTO{}, Missing: productIDs(items)}
+	}
+
+	for _, single := range singles {
+		if single.Complete {
+			savings := round(single.Total - best.Total)
+			best.Savings = &savings
+			break
+		}
+	}
+
+	return best
+}
+
+// planFor buys every item in the cheapest market of the given set
+func planFor(items []Item, table priceTable, markets []uuid.UUID) (*SplitPlanDTO, int) {
+	assigned := map[uuid.UUID][]Item{}
+	plan := &SplitPlanDTO{Missing: []uuid.UUID{}}
+	covered := 0
+
+	for _, item := range items {
+		var chosen *Offer
+		for _, marketID := range markets {
+			offer, ok := table.offer(item.ProductID, marketID)
+			if ok && (chosen == nil || offer.UnitPrice < chosen.UnitPrice) {
+				chosen = &offer
+			}
+		}
+
+		if chosen == nil {
+			plan.Missing = append(plan.Missing, item.ProductID)
+			continue
+		}
+
+		covered++
+		assigned[chosen.MarketID] = append(assigned[chosen.MarketID], item)
+	}
+
+	plan.Markets = []*MarketTotalDTO{}
+	for _, marketID := range markets {
+		marketItems, ok := assigned[marketID]
+		if !ok {
+			continue
+		}
+		total := priceIn(marketItems, table, marketID)
+		plan.Markets = append(plan.Markets, total)
+		plan.Total += total.Total
+	}
+	plan.Total = round(plan.Total)
+
+	return plan, covered
+}
+
+// priceIn prices the items available in a single market
+func priceIn(items []Item, table priceTable, marketID uuid.UUID) *MarketTotalDTO {
+	total := &MarketTotalDTO{
+		MarketID: marketID,
+		Items:    []BasketLineDTO{},
+		Missing:  []uuid.UUID{},
+	}
+
+	for _, item := range items {
+		offer, ok := table.offer(item.ProductID, marketID)
+		if !ok {
+			total.Missing = append(total.Missing, item.ProductID)
+			continue
+		}
+
+		subtotal := round(offer.UnitPrice * item.Quantity)
+		total.Items = append(total.Items, BasketLineDTO{
+			ProductID:   item.ProductID,
+			StoreID:     offer.StoreID,
+			Quantity:    item.Quantity,
+			UnitPrice:   offer.UnitPrice,
+			Subtotal:    subtotal,
+			Promotional: offer.Promotional,
+		})
+		total.Total += subtotal
+	}
+
+	total.Total = round(total.Total)
+	total.Complete = len(total.Missing) == 0
+	return total
+}
+
+// combinations calls fn with every subset of markets of size 1 to maxSize
+func combinations(markets []uuid.UUID, maxSize int, fn func([]uuid.UUID)) {
+	current := make([]uuid.UUID, 0, maxSize)
+
+	var walk func(start int)
+	walk = func(start int) {
+		if len(current) > 0 {
+			fn(append([]uuid.UUID(nil), current...))
+		}
+		if len(current) == maxSize {
+			return
+		}
+		for i := start; i < len(markets); i++ {
+			current = append(current, markets[i])
+			walk(i + 1)
+			current = current[:len(current)-1]
+		}
+	}
+
+	walk(0)
+}
+
+func betterTotal(a, b *MarketTotalDTO) bool {
+	if len(a.Missing) != len(b.Missing) {
+		return len(a.Missing) < len(b.Missing)
+	}
+	if a.Total != b.Total {
+		return a.Total < b.Total
+	}
+	return a.MarketID.String() < b.MarketID.String()
+}
+
+func marketsOf(table priceTable) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	markets := []uuid.UUID{}
+	for _, offers := range table {
+		for marketID := range offers {
+			if !seen[marketID] {
+				seen[marketID] = true
+				markets = append(markets, marketID)
+			}
+		}
+	}
+	return markets
+}
+
+func productIDs(items []Item) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	return ids
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,90 @@
+package basket
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBestSplit(t *testing.T) {
+	rice, beans, coffee := uuid.New(), uuid.New(), uuid.New()
+	marketA, marketB, marketC := uuid.New(), uuid.New(), uuid.New()
+
+	table := priceTable{}
+	table.add(Offer{ProductID: rice, MarketID: marketA, UnitPrice: 20})
+	table.add(Offer{ProductID: beans, MarketID: marketA, UnitPrice: 8})
+	table.add(Offer{ProductID: coffee, MarketID: marketA, UnitPrice: 15})
+	table.add(Offer{ProductID: rice, MarketID: marketB, UnitPrice: 18})
+	table.add(Offer{ProductID: beans, MarketID: marketB, UnitPrice: 9})
+	table.add(Offer{ProductID: coffee, MarketID: marketC, UnitPrice: 12})
+	// a cheaper offer for the same product and market wins
+	table.add(Offer{ProductID: beans, MarketID: marketB, UnitPrice: 7})
+
+	items := []Item{
+		{ProductID: rice, Quantity: 1},
+		{ProductID: beans, Quantity: 2},
+		{ProductID: coffee, Quantity: 1},
+	}
+
+	totals := marketTotals(items, table)
+	if totals[0].MarketID != marketA || !totals[0].Complete || totals[0].Total != 51 {
+		t.Fatalf("expected market A complete with 51, got %+v", totals[0])
+	}
+
+	single := bestSplit(items, table, 1)
+	if len(single.Markets) != 1 || single.Total != 51 {
+		t.Errorf("expected single market plan of 51, got %v", single.Total)
+	}
+
+	split := bestSplit(items, table, 2)
+	if split.Total != 44 || len(split.Missing) != 0 {
+		t.Errorf("expected split plan of 44 with no missing items, got %v missing %v", split.Total, split.Missing)
+	}
+	if split.Savings == nil || *split.Savings != 7 {
+		t.Errorf("expected savings of 7, got %v", split.Savings)
+	}
+
+	three := bestSplit(items, table, 3)
+	if three.Total != 44 {
+		t.Errorf("expected 3-market plan total 44, got %v", three.Total)
+	}
+}
+
+func TestBestSplitPrefersCoverage(t *testing.T) {
+	rice, beans := uuid.New(), uuid.New()
+	cheap, complete := uuid.New(), uuid.New()
+
+	table := priceTable{}
+	table.add(Offer{ProductID: rice, MarketID: cheap, UnitPrice: 1})
+	table.add(Offer{ProductID: rice, MarketID: complete, UnitPrice: 10})
+	table.add(Offer{ProductID: beans, MarketID: complete, UnitPrice: 10})
+
+	items := []Item{{ProductID: rice, Quantity: 1}, {ProductID: beans, Quantity: 1}}
+
+	plan := bestSplit(items, table, 1)
+	if len(plan.Missing) != 0 || plan.Markets[0].MarketID != complete {
+		t.Errorf("expected the complete market to win, got %+v", plan)
+	}
+}
+
+func TestNormalizeItems(t *testing.T) {
+	rice := uuid.New()
+
+	items, err := normalizeItems([]BasketItemDTO{
+		{ProductID: rice},
+		{ProductID: rice, Quantity: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Quantity != 3 {
+		t.Errorf("expected merged quantity 3, got %+v", items)
+	}
+
+	if _, err := normalizeItems(nil); err != ErrEmptyBasket {
+		t.Errorf("expected ErrEmptyBasket, got %v", err)
+	}
+	if _, err := normalizeItems([]BasketItemDTO{{ProductID: rice, Quantity: -1}}); err != ErrInvalidQuantity {
+		t.Errorf("expected ErrInvalidQuantity, got %v", err)
+	}
+}
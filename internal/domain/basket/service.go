@@ -0,0 +1,137 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"market/internal/domain/market"
+	"market/internal/domain/product_market"
+	"market/pkg/apperr"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxMarkets = 2
+	maxMarketsLimit   = 4
+	maxItems          = 100
+)
+
+var (
+	ErrEmptyBasket     = apperr.Validation("empty_basket", "basket must have at least one item")
+	ErrTooManyItems    = apperr.Validation("too_many_items", fmt.Sprintf("basket can have at most %d items", maxItems))
+	ErrInvalidQuantity = apperr.Validation("invalid_quantity", "quantity must be greater than 0")
+	ErrInvalidMarkets  = apperr.Validation("invalid_max_markets", fmt.Sprintf("max_markets must be between 1 and %d", maxMarketsLimit))
+)
+
+type UseCase interface {
+	Compare(ctx context.Context, dto *BasketCompareDTO) (*BasketComparisonDTO, error)
+}
+
+type service struct {
+	log                     *zap.SugaredLogger
+	productMarketRepository product_market.Repository
+	marketService           market.UseCase
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:                     log,
+		productMarketRepository: product_market.NewRepository(log),
+		marketService:           market.NewService(log),
+	}
+}
+
+func (s *service) Compare(ctx context.Context, dto *BasketCompareDTO) (*BasketComparisonDTO, error) {
+	items, err := normalizeItems(dto.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.MaxMarkets == 0 {
+		dto.MaxMarkets = defaultMaxMarkets
+	}
+	if dto.MaxMarkets < 1 || dto.MaxMarkets > maxMarketsLimit {
+		return nil, ErrInvalidMarkets
+	}
+
+	offers, err := s.productMarketRepository.FindActiveByProductIDs(ctx, productIDs(items))
+	if err != nil {
+		s.log.Errorw("error finding offers for basket", "error", err)
+		return nil, fmt.Errorf("error finding offers: %w", err)
+	}
+
+	table := priceTable{}
+	for _, offer := range offers {
+		table.add(Offer{
+			ProductID:   offer.ProductID,
+			MarketID:    offer.MarketID,
+			StoreID:     offer.StoreID,
+			UnitPrice:   offer.EffectivePrice(),
+			Promotional: offer.EffectivePrice() < offer.Price,
+		})
+	}
+
+	comparison := &BasketComparisonDTO{
+		Markets:   marketTotals(items, table),
+		SplitPlan: bestSplit(items, table, dto.MaxMarkets),
+	}
+
+	s.fillMarketNames(ctx, comparison)
+
+	return comparison, nil
+}
+
+// fillMarketNames is best effort, the comparison is still useful with IDs only
+func (s *service) fillMarketNames(ctx context.Context, comparison *BasketComparisonDTO) {
+	ids := make([]uuid.UUID, 0, len(comparison.Markets))
+	for _, total := range comparison.Markets {
+		ids = append(ids, total.MarketID)
+	}
+
+	names, err := s.marketService.Names(ctx, ids)
+	if err != nil {
+		s.log.Warnw("error loading market names for basket", "error", err)
+		return
+	}
+
+	for _, total := range comparison.Markets {
+		total.MarketName = names[total.MarketID]
+	}
+	for _, total := range comparison.SplitPlan.Markets {
+		total.MarketName = names[total.MarketID]
+	}
+}
+
+// normalizeItems validates quantities and merges repeated products
+func normalizeItems(dtos []BasketItemDTO) ([]Item, error) {
+	if len(dtos) == 0 {
+		return nil, ErrEmptyBasket
+	}
+	if len(dtos) > maxItems {
+		return nil, ErrTooManyItems
+	}
+
+	items := []Item{}
+	index := map[uuid.UUID]int{}
+	for _, dto := range dtos {
+		quantity := dto.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 {
+			return nil, ErrInvalidQuantity
+		}
+
+		if i, ok := index[dto.ProductID]; ok {
+			items[i].Quantity += quantity
+			continue
+		}
+		index[dto.ProductID] = len(items)
+		items = append(items, Item{ProductID: dto.ProductID, Quantity: quantity})
+	}
+
+	return items, nil
+}
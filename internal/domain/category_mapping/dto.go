@@ -0,0 +1,18 @@
+package category_mapping
+
+import "github.com/google/uuid"
+
+type CategoryMappingCreateDTO struct {
+	Provider   string     `json:"provider" validate:"required"`
+	ExternalID string     `json:"external_id" validate:"required,max=255"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+}
+
+type CategoryMappingUpdateDTO struct {
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+type CategoryMappingFilterDTO struct {
+	Provider     string
+	UnmappedOnly bool
+}
@@ -0,0 +1,36 @@
+package category_mapping
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryMapping liga uma categoria externa de um provider (ID ou caminho,
+// como "8" ou "/Carnes, Aves e Peixes/Frango/") a uma categoria interna.
+// Sem CategoryID a categoria foi vista em uma sincronização mas ainda não foi mapeada.
+type CategoryMapping struct {
+	ID         uuid.UUID  `json:"id"`
+	Provider   string     `json:"provider"`
+	ExternalID string     `json:"external_id"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func NewCategoryMapping(provider, externalID string, categoryID *uuid.UUID) *CategoryMapping {
+	return &CategoryMapping{
+		ID:         uuid.New(),
+		Provider:   provider,
+		ExternalID: externalID,
+		CategoryID: categoryID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// IsMapped checks if the external category points to an internal one
+func (m *CategoryMapping) IsMapped() bool {
+	return m.CategoryID != nil
+}
@@ -0,0 +1,164 @@
+package category_mapping
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// ListCategoryMappingsHandler godoc
+// @Summary      Listar mapeamentos de categorias
+// @Description  Lista os mapeamentos de categorias externas dos providers para categorias internas
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        provider	query		string	false	"Provider name"
+// @Param        unmapped	query		bool	false	"Only categories without internal category"
+// @Success      200		{array}		CategoryMapping
+// @Router       /admin/category-mappings [get]
+func (h *Handler) ListCategoryMappingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter := &CategoryMappingFilterDTO{
+		Provider:     r.URL.Query().Get("provider"),
+		UnmappedOnly: r.URL.Query().Get("unmapped") == "true",
+	}
+
+	mappings, err := h.usecase.List(r.Context(), filter)
+	if err != nil {
+		httpx.SendInternalServerError(w, "Failed to list category mappings")
+		return
+	}
+
+	httpx.SendSuccess(w, mappings)
+}
+
+// CreateCategoryMappingHandler godoc
+// @Summary      Criar mapeamento de categoria
+// @Description  Mapeia uma categoria externa (ID ou caminho) de um provider para uma categoria interna
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		CategoryMappingCreateDTO	true	"Mapping data"
+// @Success      201		{object}	CategoryMapping
+// @Failure      400		{object}	httpx.Problem
+// @Failure      409		{object}	httpx.Problem
+// @Router       /admin/category-mappings [post]
+func (h *Handler) CreateCategoryMappingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var dto CategoryMappingCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	mapping, err := h.usecase.Create(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, mapping)
+}
+
+// GetCategoryMappingHandler godoc
+// @Summary      Obter mapeamento de categoria
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"Mapping ID"
+// @Success      200	{object}	CategoryMapping
+// @Failure      404	{object}	httpx.Problem
+// @Router       /admin/category-mappings/{id} [get]
+func (h *Handler) GetCategoryMappingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid mapping ID format")
+		return
+	}
+
+	mapping, err := h.usecase.FindByID(r.Context(), id)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, mapping)
+}
+
+// UpdateCategoryMappingHandler godoc
+// @Summary      Atualizar mapeamento de categoria
+// @Description  Altera a categoria interna de um mapeamento; category_id nulo desfaz o mapeamento
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string						true	"Mapping ID"
+// @Param        request	body		CategoryMappingUpdateDTO	true	"Mapping data"
+// @Success      200		{object}	CategoryMapping
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /admin/category-mappings/{id} [put]
+func (h *Handler) UpdateCategoryMappingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid mapping ID format")
+		return
+	}
+
+	var dto CategoryMappingUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	mapping, err := h.usecase.Update(r.Context(), id, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, mapping)
+}
+
+// DeleteCategoryMappingHandler godoc
+// @Summary      Remover mapeamento de categoria
+// @Tags         admin
+// @Security     ApiKeyAuth
+// @Param        id		path	string	true	"Mapping ID"
+// @Success      204	"No Content"
+// @Failure      404	{object}	httpx.Problem
+// @Router       /admin/category-mappings/{id} [delete]
+func (h *Handler) DeleteCategoryMappingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid mapping ID format")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), id); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,245 @@
+package category_mapping
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"market/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Create(ctx context.Context, mapping *CategoryMapping) (*CategoryMapping, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*CategoryMapping, error)
+	List(ctx context.Context, filter *CategoryMappingFilterDTO) ([]*CategoryMapping, error)
+	Update(ctx context.Context, mapping *CategoryMapping) (*CategoryMapping, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// FindMapped returns the internal category of every mapped external ID of the provider
+	FindMapped(ctx context.Context, provider string) (map[string]uuid.UUID, error)
+	// SaveUnmapped registers external IDs seen in a sync without a mapping
+	SaveUnmapped(ctx context.Context, provider string, externalIDs []string) error
+	// IsSharedCategory tells whether the category exists and belongs to no company
+	IsSharedCategory(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type repository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO provider_category_mappings
+		(id, provider, external_id, category_id, created_at, updated_at)
+	VALUES
+		($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	RETURNING created_at, updated_at;`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &repository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, mapping *CategoryMapping) (*CategoryMapping, error) {
+	err := r.db.Stmt(ctx, r.createStatement).QueryRowContext(
+		ctx,
+		mapping.ID,
+		mapping.Provider,
+		mapping.ExternalID,
+		mapping.CategoryID,
+	).Scan(&mapping.CreatedAt, &mapping.UpdatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute Create", "error", err, "provider", mapping.Provider, "external_id", mapping.ExternalID)
+		return nil, translateError(err)
+	}
+
+	return mapping, nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id uuid.UUID) (*CategoryMapping, error) {
+	sql := `SELECT id, provider, external_id, category_id, last_seen_at, created_at, updated_at
+	FROM provider_category_mappings WHERE id = $1 LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, id)
+	if err != nil {
+		r.log.Errorw("error on execute FindByID", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		mapping, err := scanMapping(rows)
+		if err != nil {
+			r.log.Errorw("error on scan FindByID", "error", err)
+			return nil, err
+		}
+		return mapping, nil
+	}
+
+	return nil, nil
+}
+
+func (r *repository) List(ctx context.Context, filter *CategoryMappingFilterDTO) ([]*CategoryMapping, error) {
+	sql := `SELECT id, provider, external_id, category_id, last_seen_at, created_at, updated_at
+	FROM provider_category_mappings
+	WHERE ($1 = '' OR provider = $1) AND (NOT $2 OR category_id IS NULL)
+	ORDER BY provider, external_id`
+
+	rows, err := r.db.QueryContext(ctx, sql, filter.Provider, filter.UnmappedOnly)
+	if err != nil {
+		r.log.Errorw("error on execute List", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := []*CategoryMapping{}
+	for rows.Next() {
+		mapping, err := scanMapping(rows)
+		if err != nil {
+			r.log.Errorw("error on scan List", "error", err)
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error iterating List", "error", err)
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+func (r *repository) Update(ctx context.Context, mapping *CategoryMapping) (*CategoryMapping, error) {
+	sql := `UPDATE provider_category_mappings SET
+		category_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, sql, mapping.ID, mapping.CategoryID)
+	if err != nil {
+		r.log.Errorw("error on execute Update", "error", err, "id", mapping.ID)
+		return nil, translateError(err)
+	}
+
+	return r.FindByID(ctx, mapping.ID)
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `DELETE FROM provider_category_mappings WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, sql, id)
+	if err != nil {
+		r.log.Errorw("error on execute Delete", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) IsSharedCategory(ctx context.Context, id uuid.UUID) (bool, error) {
+	sql := `SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1 AND company_id IS NULL AND status != 'deleted')`
+
+	var shared bool
+	if err := r.db.QueryRowContext(ctx, sql, id).Scan(&shared); err != nil {
+		r.log.Errorw("error on execute IsSharedCategory", "error", err, "id", id)
+		return false, err
+	}
+
+	return shared, nil
+}
+
+func (r *repository) FindMapped(ctx context.Context, provider string) (map[string]uuid.UUID, error) {
+	sql := `SELECT external_id, category_id FROM provider_category_mappings
+	WHERE provider = $1 AND category_id IS NOT NULL`
+
+	rows, err := r.db.QueryContext(ctx, sql, provider)
+	if err != nil {
+		r.log.Errorw("error on execute FindMapped", "error", err, "provider", provider)
+		return nil, err
+	}
+	defer rows.Close()
+
+	mapped := map[string]uuid.UUID{}
+	for rows.Next() {
+		var externalID string
+		var categoryID uuid.UUID
+		if err = rows.Scan(&externalID, &categoryID); err != nil {
+			r.log.Errorw("error on scan FindMapped", "error", err, "provider", provider)
+			return nil, err
+		}
+		mapped[externalID] = categoryID
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error iterating FindMapped", "error", err, "provider", provider)
+		return nil, err
+	}
+
+	return mapped, nil
+}
+
+func (r *repository) SaveUnmapped(ctx context.Context, provider string, externalIDs []string) error {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO provider_category_mappings (id, provider, external_id, last_seen_at, created_at, updated_at)
+	SELECT uuid_generate_v4(), $1, external_id, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+	FROM unnest($2::text[]) AS external_id
+	ON CONFLICT (provider, external_id) DO UPDATE SET last_seen_at = CURRENT_TIMESTAMP`
+
+	_, err := r.db.ExecContext(ctx, sql, provider, pq.Array(externalIDs))
+	if err != nil {
+		r.log.Errorw("error on execute SaveUnmapped", "error", err, "provider", provider)
+		return err
+	}
+
+	return nil
+}
+
+func scanMapping(rows *sql.Rows) (*CategoryMapping, error) {
+	var mapping CategoryMapping
+	err := rows.Scan(
+		&mapping.ID,
+		&mapping.Provider,
+		&mapping.ExternalID,
+		&mapping.CategoryID,
+		&mapping.LastSeenAt,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// translateError maps constraint violations to domain errors
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrMappingExists
+		case "23503":
+			return ErrCategoryNotFound
+		}
+	}
+	return err
+}
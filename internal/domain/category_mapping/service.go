@@ -0,0 +1,133 @@
+package category_mapping
+
+import (
+	"context"
+	"fmt"
+	"market/pkg/apperr"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMappingNotFound  = apperr.NotFound("category_mapping_not_found", "category mapping not found")
+	ErrMappingExists    = apperr.Conflict("category_mapping_exists", "category mapping already exists for this provider")
+	ErrCategoryNotFound = apperr.Validation("category_not_found", "category not found")
+	ErrInvalidMapping   = apperr.Validation("invalid_category_mapping", "provider and external_id are required")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, input *CategoryMappingCreateDTO) (*CategoryMapping, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*CategoryMapping, error)
+	List(ctx context.Context, filter *CategoryMappingFilterDTO) ([]*CategoryMapping, error)
+	Update(ctx context.Context, id uuid.UUID, input *CategoryMappingUpdateDTO) (*CategoryMapping, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type service struct {
+	log        *zap.SugaredLogger
+	repository Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:        log,
+		repository: NewRepository(log),
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *CategoryMappingCreateDTO) (*CategoryMapping, error) {
+	provider := strings.TrimSpace(input.Provider)
+	externalID := strings.TrimSpace(input.ExternalID)
+	if provider == "" || externalID == "" {
+		return nil, ErrInvalidMapping
+	}
+
+	if err := s.ensureSharedCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	mapping, err := s.repository.Create(ctx, NewCategoryMapping(provider, externalID, input.CategoryID))
+	if err != nil {
+		s.log.Errorw("error creating category mapping", "error", err, "provider", provider, "external_id", externalID)
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (s *service) FindByID(ctx context.Context, id uuid.UUID) (*CategoryMapping, error) {
+	mapping, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		s.log.Errorw("error finding category mapping", "error", err, "id", id)
+		return nil, err
+	}
+	if mapping == nil {
+		return nil, ErrMappingNotFound
+	}
+
+	return mapping, nil
+}
+
+func (s *service) List(ctx context.Context, filter *CategoryMappingFilterDTO) ([]*CategoryMapping, error) {
+	mappings, err := s.repository.List(ctx, filter)
+	if err != nil {
+		s.log.Errorw("error listing category mappings", "error", err)
+		return nil, fmt.Errorf("error listing category mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+func (s *service) Update(ctx context.Context, id uuid.UUID, input *CategoryMappingUpdateDTO) (*CategoryMapping, error) {
+	mapping, err := s.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureSharedCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+	mapping.CategoryID = input.CategoryID
+
+	updated, err := s.repository.Update(ctx, mapping)
+	if err != nil {
+		s.log.Errorw("error updating category mapping", "error", err, "id", id)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		s.log.Errorw("error deleting category mapping", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+// ensureSharedCategory only lets mappings point to shared categories, the
+// sync applies them to products every company sees
+func (s *service) ensureSharedCategory(ctx context.Context, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+
+	shared, err := s.repository.IsSharedCategory(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if !shared {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
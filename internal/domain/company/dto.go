@@ -0,0 +1,11 @@
+package company
+
+import "github.com/google/uuid"
+
+type CompanyCreateDTO struct {
+	Name string `json:"name" validate:"required,max=120"`
+}
+
+type MemberCreateDTO struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
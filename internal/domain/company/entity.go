@@ -0,0 +1,43 @@
+package company
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CompanyStatus string
+
+const (
+	CompanyStatusActive   CompanyStatus = "active"
+	CompanyStatusInactive CompanyStatus = "inactive"
+)
+
+// DefaultCompanyID is the company created by the companies migration, it holds
+// the users and data that existed before companies. New users get their own.
+var DefaultCompanyID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Company is a client of the platform. Lists, attachments, company categories
+// and manual prices belong to a single company.
+type Company struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Status    CompanyStatus `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func NewCompany(name string) *Company {
+	return &Company{
+		ID:     uuid.New(),
+		Name:   name,
+		Status: CompanyStatusActive,
+	}
+}
+
+// Member links a user to a company
+type Member struct {
+	CompanyID uuid.UUID `json:"company_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
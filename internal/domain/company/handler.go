@@ -0,0 +1,149 @@
+package company
+
+import (
+	"market/pkg/httpx"
+	"market/pkg/security"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// CreateCompanyHandler godoc
+// @Summary      Criar empresa
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		CompanyCreateDTO	true	"Company data"
+// @Success      201		{object}	Company
+// @Failure      400		{object}	httpx.Problem
+// @Router       /admin/companies [post]
+func (h *Handler) CreateCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	var dto CompanyCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	company, err := h.usecase.Create(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, company)
+}
+
+// ListCompaniesHandler godoc
+// @Summary      Listar empresas
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200	{array}		Company
+// @Router       /admin/companies [get]
+func (h *Handler) ListCompaniesHandler(w http.ResponseWriter, r *http.Request) {
+	companies, err := h.usecase.List(r.Context())
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, companies)
+}
+
+// AddMemberHandler godoc
+// @Summary      Adicionar usuário à empresa
+// @Tags         admin
+// @Accept       json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"Company ID"
+// @Param        request	body		MemberCreateDTO	true	"User"
+// @Success      204
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /admin/companies/{id}/users [post]
+func (h *Handler) AddMemberHandler(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid company ID")
+		return
+	}
+
+	var dto MemberCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	if err := h.usecase.AddMember(r.Context(), companyID, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMemberHandler godoc
+// @Summary      Remover usuário da empresa
+// @Tags         admin
+// @Security     ApiKeyAuth
+// @Param        id			path	string	true	"Company ID"
+// @Param        user_id	path	string	true	"User ID"
+// @Success      204
+// @Failure      404	{object}	httpx.Problem
+// @Router       /admin/companies/{id}/users/{user_id} [delete]
+func (h *Handler) RemoveMemberHandler(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid company ID")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("user_id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.usecase.RemoveMember(r.Context(), companyID, userID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MyCompaniesHandler godoc
+// @Summary      Listar minhas empresas
+// @Description  Retorna as empresas do usuário autenticado
+// @Tags         auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200	{array}		Company
+// @Failure      401	{object}	httpx.Problem
+// @Router       /auth/me/companies [get]
+func (h *Handler) MyCompaniesHandler(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := security.GetUser(r.Context())
+	if err != nil {
+		httpx.SendUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	companies, err := h.usecase.ListByUser(r.Context(), userCtx.UserID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, companies)
+}
@@ -0,0 +1,202 @@
+package company
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"market/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Create(ctx context.Context, company *Company) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Company, error)
+	List(ctx context.Context) ([]*Company, error)
+	// ListByUser returns the active companies of the user, oldest membership first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*Company, error)
+	IsMember(ctx context.Context, companyID, userID uuid.UUID) (bool, error)
+	AddMember(ctx context.Context, member *Member) error
+	RemoveMember(ctx context.Context, companyID, userID uuid.UUID) error
+}
+
+type repository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+const companyColumns = `c.id, c.name, c.status, c.created_at, c.updated_at`
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO companies
+		(id, name, status, created_at, updated_at)
+	VALUES
+		($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	RETURNING created_at, updated_at`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &repository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, company *Company) error {
+	err := r.db.Stmt(ctx, r.createStatement).QueryRowContext(
+		ctx,
+		company.ID,
+		company.Name,
+		company.Status,
+	).Scan(&company.CreatedAt, &company.UpdatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute Create", "error", err, "name", company.Name)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id uuid.UUID) (*Company, error) {
+	sql := `SELECT ` + companyColumns + ` FROM companies c WHERE c.id = $1 LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, id)
+	if err != nil {
+		r.log.Errorw("error on execute FindByID", "error", err, "id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			r.log.Errorw("error on scan FindByID", "error", err, "id", id)
+			return nil, err
+		}
+		return company, nil
+	}
+
+	return nil, nil
+}
+
+func (r *repository) List(ctx context.Context) ([]*Company, error) {
+	sql := `SELECT ` + companyColumns + ` FROM companies c ORDER BY c.name`
+
+	rows, err := r.db.QueryContext(ctx, sql)
+	if err != nil {
+		r.log.Errorw("error on execute List", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanCompanies(rows, "List")
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*Company, error) {
+	sql := `SELECT ` + companyColumns + `
+	FROM companies c
+	INNER JOIN company_users cu ON cu.company_id = c.id
+	WHERE cu.user_id = $1 AND c.status = 'active'
+	ORDER BY cu.created_at, c.id`
+
+	rows, err := r.db.QueryContext(ctx, sql, userID)
+	if err != nil {
+		r.log.Errorw("error on execute ListByUser", "error", err, "user_id", userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanCompanies(rows, "ListByUser")
+}
+
+func (r *repository) IsMember(ctx context.Context, companyID, userID uuid.UUID) (bool, error) {
+	sql := `SELECT EXISTS (
+		SELECT 1 FROM company_users cu
+		INNER JOIN companies c ON c.id = cu.company_id AND c.status = 'active'
+		WHERE cu.company_id = $1 AND cu.user_id = $2
+	)`
+
+	var member bool
+	if err := r.db.QueryRowContext(ctx, sql, companyID, userID).Scan(&member); err != nil {
+		r.log.Errorw("error on execute IsMember", "error", err, "company_id", companyID, "user_id", userID)
+		return false, err
+	}
+	return member, nil
+}
+
+func (r *repository) AddMember(ctx context.Context, member *Member) error {
+	sql := `INSERT INTO company_users (company_id, user_id, created_at)
+	VALUES ($1, $2, CURRENT_TIMESTAMP)
+	ON CONFLICT (company_id, user_id) DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, sql, member.CompanyID, member.UserID); err != nil {
+		r.log.Errorw("error on execute AddMember", "error", err, "company_id", member.CompanyID, "user_id", member.UserID)
+		return translateError(err)
+	}
+	return nil
+}
+
+func (r *repository) RemoveMember(ctx context.Context, companyID, userID uuid.UUID) error {
+	sql := `DELETE FROM company_users WHERE company_id = $1 AND user_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, sql, companyID, userID); err != nil {
+		r.log.Errorw("error on execute RemoveMember", "error", err, "company_id", companyID, "user_id", userID)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) scanCompanies(rows *sql.Rows, operation string) ([]*Company, error) {
+	companies := []*Company{}
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			r.log.Errorw("error on scan "+operation, "error", err)
+			return nil, err
+		}
+		companies = append(companies, company)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.log.Errorw("error on iterate "+operation, "error", err)
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+func scanCompany(rows *sql.Rows) (*Company, error) {
+	var company Company
+	err := rows.Scan(
+		&company.ID,
+		&company.Name,
+		&company.Status,
+		&company.CreatedAt,
+		&company.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &company, nil
+}
+
+// translateError maps a missing user to a domain error
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrUserNotFound
+	}
+	return err
+}
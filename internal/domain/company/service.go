@@ -0,0 +1,162 @@
+package company
+
+import (
+	"context"
+	"market/pkg/apperr"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCompanyNotFound = apperr.NotFound("company_not_found", "company not found")
+	ErrUserNotFound    = apperr.NotFound("user_not_found", "user not found")
+	ErrNameRequired    = apperr.Validation("name_required", "name is required")
+	ErrNotMember       = apperr.Forbidden("not_company_member", "user is not a member of the company")
+	ErrNoCompany       = apperr.Forbidden("no_active_company", "user does not belong to any active company")
+)
+
+// maxNameLength is the size of companies.name
+const maxNameLength = 120
+
+type UseCase interface {
+	Create(ctx context.Context, input *CompanyCreateDTO) (*Company, error)
+	List(ctx context.Context) ([]*Company, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*Company, error)
+	AddMember(ctx context.Context, companyID uuid.UUID, input *MemberCreateDTO) error
+	RemoveMember(ctx context.Context, companyID, userID uuid.UUID) error
+
+	// Resolve returns the company the user acts on: the preferred one when the
+	// user still belongs to it, otherwise the oldest membership
+	Resolve(ctx context.Context, userID uuid.UUID, preferred *uuid.UUID) (uuid.UUID, error)
+	// CreatePersonal creates a company with the user as its only member, used
+	// on sign up so new accounts never join a company that already has data
+	CreatePersonal(ctx context.Context, userID uuid.UUID, name string) (*Company, error)
+}
+
+type service struct {
+	log        *zap.SugaredLogger
+	repository Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:        log,
+		repository: NewRepository(log),
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *CompanyCreateDTO) (*Company, error) {
+	if input == nil || strings.TrimSpace(input.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
+	company := NewCompany(strings.TrimSpace(input.Name))
+	if err := s.repository.Create(ctx, company); err != nil {
+		s.log.Errorw("error creating company", "error", err)
+		return nil, err
+	}
+
+	return company, nil
+}
+
+func (s *service) List(ctx context.Context) ([]*Company, error) {
+	return s.repository.List(ctx)
+}
+
+func (s *service) ListByUser(ctx context.Context, userID uuid.UUID) ([]*Company, error) {
+	return s.repository.ListByUser(ctx, userID)
+}
+
+func (s *service) AddMember(ctx context.Context, companyID uuid.UUID, input *MemberCreateDTO) error {
+	if input == nil || input.UserID == uuid.Nil {
+		return ErrUserNotFound
+	}
+	if err := s.ensureCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	if err := s.repository.AddMember(ctx, &Member{CompanyID: companyID, UserID: input.UserID}); err != nil {
+		return err
+	}
+
+	s.log.Infow("user added to company", "company_id", companyID, "user_id", input.UserID)
+	return nil
+}
+
+// RemoveMember takes the user out of the company. Access tokens already issued
+// keep working until they expire, the next refresh picks another company.
+func (s *service) RemoveMember(ctx context.Context, companyID, userID uuid.UUID) error {
+	if err := s.ensureCompany(ctx, companyID); err != nil {
+		return err
+	}
+
+	if err := s.repository.RemoveMember(ctx, companyID, userID); err != nil {
+		return err
+	}
+
+	s.log.Infow("user removed from company", "company_id", companyID, "user_id", userID)
+	return nil
+}
+
+func (s *service) Resolve(ctx context.Context, userID uuid.UUID, preferred *uuid.UUID) (uuid.UUID, error) {
+	if preferred != nil {
+		member, err := s.repository.IsMember(ctx, *preferred, userID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if member {
+			return *preferred, nil
+		}
+	}
+
+	companies, err := s.repository.ListByUser(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(companies) == 0 {
+		return uuid.Nil, ErrNoCompany
+	}
+
+	return companies[0].ID, nil
+}
+
+func (s *service) CreatePersonal(ctx context.Context, userID uuid.UUID, name string) (*Company, error) {
+	company := NewCompany(personalName(name))
+	if err := s.repository.Create(ctx, company); err != nil {
+		s.log.Errorw("error creating personal company", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	if err := s.repository.AddMember(ctx, &Member{CompanyID: company.ID, UserID: userID}); err != nil {
+		return nil, err
+	}
+
+	return company, nil
+}
+
+// personalName fits the user name in the company name column
+func personalName(name string) string {
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = strings.TrimSpace(string(runes[:maxNameLength]))
+	}
+	if name == "" {
+		return "Personal"
+	}
+	return name
+}
+
+func (s *service) ensureCompany(ctx context.Context, id uuid.UUID) error {
+	found, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return ErrCompanyNotFound
+	}
+	return nil
+}
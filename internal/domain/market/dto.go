@@ -3,8 +3,14 @@ package market
 import "github.com/google/uuid"
 
 type MarketCreateDTO struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string `json:"name" validate:"required,max=120"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+type MarketUpdateDTO struct {
+	Name        *string       `json:"name,omitempty" validate:"omitempty,max=120"`
+	Description *string       `json:"description,omitempty" validate:"omitempty,max=255"`
+	Status      *MarketStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
 }
 
 type MarketFoundDTO struct {
@@ -15,3 +21,44 @@ type MarketFoundDTO struct {
 	CreatedAt   string    `json:"created_at"`
 	UpdatedAt   string    `json:"updated_at"`
 }
+
+// Store DTOs
+type StoreCreateDTO struct {
+	Name         string       `json:"name" validate:"required,max=120"`
+	Address      string       `json:"address" validate:"required,max=255"`
+	Number       *string      `json:"number,omitempty" validate:"omitempty,max=20"`
+	District     *string      `json:"district,omitempty" validate:"omitempty,max=100"`
+	City         string       `json:"city" validate:"required,max=100"`
+	StateID      string       `json:"state_id" validate:"required,len=2"`
+	CEP          string       `json:"cep" validate:"required"`
+	Latitude     float64      `json:"latitude" validate:"min=-90,max=90"`
+	Longitude    float64      `json:"longitude" validate:"min=-180,max=180"`
+	OpeningHours OpeningHours `json:"opening_hours,omitempty"`
+}
+
+type StoreUpdateDTO struct {
+	Name         *string       `json:"name,omitempty" validate:"omitempty,max=120"`
+	Address      *string       `json:"address,omitempty" validate:"omitempty,max=255"`
+	Number       *string       `json:"number,omitempty" validate:"omitempty,max=20"`
+	District     *string       `json:"district,omitempty" validate:"omitempty,max=100"`
+	City         *string       `json:"city,omitempty" validate:"omitempty,max=100"`
+	StateID      *string       `json:"state_id,omitempty" validate:"omitempty,len=2"`
+	CEP          *string       `json:"cep,omitempty"`
+	Latitude     *float64      `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude    *float64      `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	OpeningHours OpeningHours  `json:"opening_hours,omitempty"`
+	Status       *MarketStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+}
+
+type NearbyFilterDTO struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Limit     int
+}
+
+type NearbyStoreDTO struct {
+	Store
+	MarketName string  `json:"market_name"`
+	DistanceKm float64 `json:"distance_km"`
+}
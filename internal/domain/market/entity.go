@@ -1,6 +1,9 @@
 package market
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -57,3 +60,76 @@ func (o *market) Delete() {
 func (o *market) IsActive() bool {
 	return o.Status == MarketStatusActive
 }
+
+// OpeningHour is the opening window of a store on a given weekday (0 = Sunday)
+type OpeningHour struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// OpeningHours is stored as JSONB in market_stores
+type OpeningHours []OpeningHour
+
+// Store represents a physical branch of a market
+type Store struct {
+	ID           uuid.UUID    `json:"id"`
+	MarketID     uuid.UUID    `json:"market_id"`
+	Name         string       `json:"name"`
+	Address      string       `json:"address"`
+	Number       *string      `json:"number,omitempty"`
+	District     *string      `json:"district,omitempty"`
+	City         string       `json:"city"`
+	StateID      string       `json:"state_id"`
+	CEP          string       `json:"cep"`
+	Latitude     float64      `json:"latitude"`
+	Longitude    float64      `json:"longitude"`
+	OpeningHours OpeningHours `json:"opening_hours"`
+	Status       MarketStatus `json:"status"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// Value implements driver.Valuer so opening hours can be written as JSONB
+func (o OpeningHours) Value() (driver.Value, error) {
+	if o == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(o)
+}
+
+// Scan implements sql.Scanner for the JSONB opening_hours column
+func (o *OpeningHours) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*o = OpeningHours{}
+		return nil
+	case []byte:
+		return json.Unmarshal(value, o)
+	case string:
+		return json.Unmarshal([]byte(value), o)
+	default:
+		return fmt.Errorf("unsupported opening hours type %T", src)
+	}
+}
+
+// Validate checks weekdays and HH:MM times
+func (o OpeningHours) Validate() error {
+	for _, hour := range o {
+		if hour.Weekday < 0 || hour.Weekday > 6 {
+			return fmt.Errorf("%w: invalid weekday %d", ErrInvalidOpeningHours, hour.Weekday)
+		}
+		opens, err := time.Parse("15:04", hour.Opens)
+		if err != nil {
+			return fmt.Errorf("%w: invalid opening time %q", ErrInvalidOpeningHours, hour.Opens)
+		}
+		closes, err := time.Parse("15:04", hour.Closes)
+		if err != nil {
+			return fmt.Errorf("%w: invalid closing time %q", ErrInvalidOpeningHours, hour.Closes)
+		}
+		if !closes.After(opens) {
+			return fmt.Errorf("%w: closing time must be after opening time on weekday %d", ErrInvalidOpeningHours, hour.Weekday)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+package market
+
+import (
+	"market/pkg/httpx"
+	"market/pkg/query"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// CreateMarketHandler godoc
+// @Summary      Criar mercado
+// @Tags         markets
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		MarketCreateDTO	true	"Market data"
+// @Success      201		{object}	MarketFoundDTO
+// @Failure      400		{object}	httpx.Problem
+// @Router       /markets [post]
+func (h *Handler) CreateMarketHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var dto MarketCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	market, err := h.usecase.Create(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, market)
+}
+
+// ListMarketsHandler godoc
+// @Summary      Listar mercados
+// @Description  Lista os mercados com paginação por página ou cursor, filtros e ordenação
+// @Tags         markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        status			query		string	false	"Status (active, inactive)"
+// @Param        created_at_gte	query		string	false	"Created from (YYYY-MM-DD or RFC 3339)"
+// @Param        created_at_lte	query		string	false	"Created until (YYYY-MM-DD or RFC 3339)"
+// @Param        sort			query		string	false	"Sort fields, - for descending (name, created_at)"
+// @Param        page			query		int		false	"Page (default 1)"
+// @Param        page_size		query		int		false	"Page size (default 50, max 200)"
+// @Param        cursor			query		string	false	"next_cursor of the previous page"
+// @Success      200			{object}	query.Page[MarketFoundDTO]
+// @Failure      400			{object}	httpx.Problem
+// @Router       /markets [get]
+func (h *Handler) ListMarketsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params, err := query.Parse(r.URL.Query(), marketQuery)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	markets, err := h.usecase.List(r.Context(), params)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, markets)
+}
+
+// GetMarketHandler godoc
+// @Summary      Obter mercado
+// @Tags         markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"Market ID"
+// @Success      200	{object}	MarketFoundDTO
+// @Failure      404	{object}	httpx.Problem
+// @Router       /markets/{id} [get]
+func (h *Handler) GetMarketHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return
+	}
+
+	market, err := h.usecase.FindByID(r.Context(), marketID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, market)
+}
+
+// UpdateMarketHandler godoc
+// @Summary      Atualizar mercado
+// @Tags         markets
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"Market ID"
+// @Param        request	body		MarketUpdateDTO	true	"Market data"
+// @Success      200		{object}	MarketFoundDTO
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /markets/{id} [put]
+func (h *Handler) UpdateMarketHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return
+	}
+
+	var dto MarketUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	market, err := h.usecase.Update(r.Context(), marketID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, market)
+}
+
+// DeleteMarketHandler godoc
+// @Summary      Remover mercado
+// @Description  Marca o mercado como removido (soft delete)
+// @Tags         markets
+// @Security     ApiKeyAuth
+// @Param        id		path	string	true	"Market ID"
+// @Success      204	"No Content"
+// @Failure      404	{object}	httpx.Problem
+// @Router       /markets/{id} [delete]
+func (h *Handler) DeleteMarketHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), marketID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NearbyStoresHandler godoc
+// @Summary      Lojas próximas
+// @Description  Lista as lojas ativas dentro do raio informado, da mais próxima para a mais distante
+// @Tags         markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        lat		query		number	true	"Latitude"
+// @Param        lng		query		number	true	"Longitude"
+// @Param        radius_km	query		number	false	"Radius in km (default 5, max 50)"
+// @Param        limit		query		int		false	"Max results (default 20, max 100)"
+// @Success      200		{array}		NearbyStoreDTO
+// @Failure      400		{object}	httpx.Problem
+// @Router       /markets/nearby [get]
+func (h *Handler) NearbyStoresHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	filter := &NearbyFilterDTO{}
+
+	var err error
+	if filter.Latitude, err = strconv.ParseFloat(query.Get("lat"), 64); err != nil {
+		httpx.SendBadRequest(w, "lat is required and must be a number")
+		return
+	}
+	if filter.Longitude, err = strconv.ParseFloat(query.Get("lng"), 64); err != nil {
+		httpx.SendBadRequest(w, "lng is required and must be a number")
+		return
+	}
+	if value := query.Get("radius_km"); value != "" {
+		if filter.RadiusKm, err = strconv.ParseFloat(value, 64); err != nil {
+			httpx.SendBadRequest(w, "radius_km must be a number")
+			return
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil {
+			httpx.SendBadRequest(w, "limit must be an integer")
+			return
+		}
+	}
+
+	stores, err := h.usecase.Nearby(r.Context(), filter)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, stores)
+}
+
+// CreateStoreHandler godoc
+// @Summary      Criar loja
+// @Description  Cadastra uma filial do mercado com endereço, CEP, coordenadas e horário de funcionamento
+// @Tags         markets
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"Market ID"
+// @Param        request	body		StoreCreateDTO	true	"Store data"
+// @Success      201		{object}	Store
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /markets/{id}/stores [post]
+func (h *Handler) CreateStoreHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return
+	}
+
+	var dto StoreCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	store, err := h.usecase.CreateStore(r.Context(), marketID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, store)
+}
+
+// ListStoresHandler godoc
+// @Summary      Listar lojas do mercado
+// @Tags         markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"Market ID"
+// @Success      200	{array}		Store
+// @Failure      404	{object}	httpx.Problem
+// @Router       /markets/{id}/stores [get]
+func (h *Handler) ListStoresHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return
+	}
+
+	stores, err := h.usecase.ListStores(r.Context(), marketID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, stores)
+}
+
+// GetStoreHandler godoc
+// @Summary      Obter loja
+// @Tags         markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string	true	"Market ID"
+// @Param        store_id	path		string	true	"Store ID"
+// @Success      200		{object}	Store
+// @Failure      404		{object}	httpx.Problem
+// @Router       /markets/{id}/stores/{store_id} [get]
+func (h *Handler) GetStoreHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, storeID, ok := parseStorePath(w, r)
+	if !ok {
+		return
+	}
+
+	store, err := h.usecase.FindStore(r.Context(), marketID, storeID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, store)
+}
+
+// UpdateStoreHandler godoc
+// @Summary      Atualizar loja
+// @Tags         markets
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"Market ID"
+// @Param        store_id	path		string			true	"Store ID"
+// @Param        request	body		StoreUpdateDTO	true	"Store data"
+// @Success      200		{object}	Store
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /markets/{id}/stores/{store_id} [put]
+func (h *Handler) UpdateStoreHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, storeID, ok := parseStorePath(w, r)
+	if !ok {
+		return
+	}
+
+	var dto StoreUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	store, err := h.usecase.UpdateStore(r.Context(), marketID, storeID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, store)
+}
+
+// DeleteStoreHandler godoc
+// @Summary      Remover loja
+// @Tags         markets
+// @Security     ApiKeyAuth
+// @Param        id			path	string	true	"Market ID"
+// @Param        store_id	path	string	true	"Store ID"
+// @Success      204		"No Content"
+// @Failure      404		{object}	httpx.Problem
+// @Router       /markets/{id}/stores/{store_id} [delete]
+func (h *Handler) DeleteStoreHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	marketID, storeID, ok := parseStorePath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.DeleteStore(r.Context(), marketID, storeID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseStorePath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid market ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	storeID, err := uuid.Parse(r.PathValue("store_id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid store ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return marketID, storeID, true
+}
@@ -1,16 +1,24 @@
 package market
 
 import (
+	"context"
 	"database/sql"
 	"market/pkg/database"
+	"market/pkg/query"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
-	Create(market *market) (*market, error)
-	FindByID(id uuid.UUID) (*market, error)
+	Create(ctx context.Context, market *market) (*market, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*market, error)
+	// NamesByIDs returns the names of the markets found, keyed by ID
+	NamesByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]string, error)
+	List(ctx context.Context, params *query.Params) ([]*market, int, error)
+	Update(ctx context.Context, market *market) (*market, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type repository struct {
@@ -19,6 +27,32 @@ type repository struct {
 	createStatement *sql.Stmt
 }
 
+// marketQuery is what clients may filter and sort market listings by
+var marketQuery = &query.Schema{
+	Fields: map[string]query.Field{
+		"id":         {Column: "id", Type: query.UUID},
+		"name":       {Column: "name", Type: query.String, Sortable: true},
+		"status":     {Column: "status", Type: query.String, Ops: []query.Op{query.Eq}, Values: []string{"active", "inactive"}},
+		"created_at": {Column: "created_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: []query.Sort{{Field: "name"}},
+	DefaultSize: 50,
+	MaxSize:     200,
+}
+
+// marketSortValue feeds the next cursor of market listings
+func marketSortValue(market *market, field string) any {
+	switch field {
+	case "name":
+		return market.Name
+	case "created_at":
+		return market.CreatedAt
+	default:
+		return market.ID
+	}
+}
+
 func NewRepository(
 	log *zap.SugaredLogger,
 ) Repository {
@@ -26,9 +60,9 @@ func NewRepository(
 	dbInstance := database.GetInstance(log)
 
 	insert := `INSERT INTO public.markets
-		(id, name, description, created_at, updated_at)
+		(id, name, description, status, created_at, updated_at)
 	VALUES
-		($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
+		($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
 
 	createStatement, err := dbInstance.Prepare(insert)
 	if err != nil {
@@ -43,11 +77,13 @@ func NewRepository(
 	}
 }
 
-func (o *repository) Create(market *market) (*market, error) {
-	_, err := o.createStatement.Exec(
+func (o *repository) Create(ctx context.Context, market *market) (*market, error) {
+	_, err := o.db.Stmt(ctx, o.createStatement).ExecContext(
+		ctx,
 		market.ID,
 		market.Name,
 		market.Description,
+		market.Status,
 	)
 
 	if err != nil {
@@ -58,10 +94,10 @@ func (o *repository) Create(market *market) (*market, error) {
 	return market, nil
 }
 
-func (o *repository) FindByID(id uuid.UUID) (*market, error) {
-	sql := `SELECT id, name, description, created_at, updated_at
-	FROM markets WHERE id = $1 LIMIT 1`
-	row, err := o.db.Query(sql, id)
+func (o *repository) FindByID(ctx context.Context, id uuid.UUID) (*market, error) {
+	sql := `SELECT id, name, description, status, created_at, updated_at
+	FROM markets WHERE id = $1 AND status != 'deleted' LIMIT 1`
+	row, err := o.db.QueryContext(ctx, sql, id)
 
 	if err != nil {
 		o.log.Errorw("error on execute FindByID", "error", err)
@@ -76,6 +112,7 @@ func (o *repository) FindByID(id uuid.UUID) (*market, error) {
 			&org.ID,
 			&org.Name,
 			&org.Description,
+			&org.Status,
 			&org.CreatedAt,
 			&org.UpdatedAt,
 		)
@@ -89,10 +126,38 @@ func (o *repository) FindByID(id uuid.UUID) (*market, error) {
 	return nil, nil
 }
 
-func (o *repository) FindByName(name string) (*market, error) {
+func (o *repository) NamesByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	sql := `SELECT id, name FROM markets WHERE id = ANY($1) AND status != 'deleted'`
+	rows, err := o.db.QueryContext(ctx, sql, pq.Array(ids))
+	if err != nil {
+		o.log.Errorw("error on execute NamesByIDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[uuid.UUID]string, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		var name string
+		if err = rows.Scan(&id, &name); err != nil {
+			o.log.Errorw("error on scan NamesByIDs", "error", err)
+			return nil, err
+		}
+		names[id] = name
+	}
+
+	if err = rows.Err(); err != nil {
+		o.log.Errorw("error iterating NamesByIDs", "error", err)
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (o *repository) FindByName(ctx context.Context, name string) (*market, error) {
 	sql := `SELECT id, name, description, created_at, updated_at
 	FROM markets WHERE name = $1 LIMIT 1`
-	row, err := o.db.Query(sql, name)
+	row, err := o.db.QueryContext(ctx, sql, name)
 
 	if err != nil {
 		o.log.Errorw("error on execute FindByName", "error", err)
@@ -119,3 +184,86 @@ func (o *repository) FindByName(name string) (*market, error) {
 
 	return nil, nil
 }
+
+func (o *repository) List(ctx context.Context, params *query.Params) ([]*market, int, error) {
+	b := params.NewBuilder()
+	where := `WHERE status != 'deleted'` + b.Filters()
+
+	var total int
+	if err := o.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM markets `+where, b.Args()...).Scan(&total); err != nil {
+		o.log.Errorw("error on count List", "error", err)
+		return nil, 0, err
+	}
+
+	sql := `SELECT id, name, description, status, created_at, updated_at
+	FROM markets ` + where + b.Page()
+
+	rows, err := o.db.QueryContext(ctx, sql, b.Args()...)
+	if err != nil {
+		o.log.Errorw("error on execute List", "error", err)
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	markets := []*market{}
+	for rows.Next() {
+		var org market
+		err = rows.Scan(
+			&org.ID,
+			&org.Name,
+			&org.Description,
+			&org.Status,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+		)
+		if err != nil {
+			o.log.Errorw("error on scan List", "error", err)
+			return nil, 0, err
+		}
+		markets = append(markets, &org)
+	}
+
+	if err = rows.Err(); err != nil {
+		o.log.Errorw("error on iterate List", "error", err)
+		return nil, 0, err
+	}
+
+	return markets, total, nil
+}
+
+func (o *repository) Update(ctx context.Context, market *market) (*market, error) {
+	sql := `UPDATE markets SET
+		name = $2, description = $3, status = $4, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING created_at, updated_at`
+
+	err := o.db.QueryRowContext(
+		ctx,
+		sql,
+		market.ID,
+		market.Name,
+		market.Description,
+		market.Status,
+	).Scan(&market.CreatedAt, &market.UpdatedAt)
+
+	if err != nil {
+		o.log.Errorw("error on execute Update", "error", err, "id", market.ID)
+		return nil, err
+	}
+
+	return market, nil
+}
+
+// Delete marks the market as deleted, its prices and stores are kept for history
+func (o *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE markets SET status = 'deleted', updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := o.db.ExecContext(ctx, sql, id)
+	if err != nil {
+		o.log.Errorw("error on execute Delete", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
@@ -1,59 +1,397 @@
 package market
 
 import (
+	"context"
+	"fmt"
+	"market/pkg/apperr"
+	"market/pkg/geo"
+	"market/pkg/query"
+	"sort"
+	"strings"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultRadiusKm    = 5
+	maxRadiusKm        = 50
+	defaultNearbyLimit = 20
+	maxNearbyLimit     = 100
+)
+
+var (
+	ErrMarketNotFound = apperr.NotFound("market_not_found", "market not found")
+	ErrStoreNotFound  = apperr.NotFound("store_not_found", "store not found")
+	ErrInvalidStatus  = apperr.Validation("invalid_status", "status must be active or inactive")
+	ErrInvalidCEP     = apperr.Validation("invalid_cep", "cep must have 8 digits")
+	ErrInvalidRadius  = apperr.Validation("invalid_radius", fmt.Sprintf("radius_km must be greater than 0 and at most %d", maxRadiusKm))
+	ErrNameRequired   = apperr.Validation("name_required", "name is required")
+
+	ErrInvalidOpeningHours = apperr.Validation("invalid_opening_hours", "invalid opening hours")
+)
+
 type UseCase interface {
-	Create(input *MarketCreateDTO) (*MarketFoundDTO, error)
-	FindByID(id uuid.UUID) (*MarketFoundDTO, error)
+	Create(ctx context.Context, input *MarketCreateDTO) (*MarketFoundDTO, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*MarketFoundDTO, error)
+	// Names returns the names of the markets found, keyed by ID
+	Names(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]string, error)
+	List(ctx context.Context, params *query.Params) (*query.Page[*MarketFoundDTO], error)
+	Update(ctx context.Context, id uuid.UUID, input *MarketUpdateDTO) (*MarketFoundDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	CreateStore(ctx context.Context, marketID uuid.UUID, input *StoreCreateDTO) (*Store, error)
+	FindStore(ctx context.Context, marketID, storeID uuid.UUID) (*Store, error)
+	ListStores(ctx context.Context, marketID uuid.UUID) ([]*Store, error)
+	UpdateStore(ctx context.Context, marketID, storeID uuid.UUID, input *StoreUpdateDTO) (*Store, error)
+	DeleteStore(ctx context.Context, marketID, storeID uuid.UUID) error
+	Nearby(ctx context.Context, filter *NearbyFilterDTO) ([]*NearbyStoreDTO, error)
 }
 
 type service struct {
-	log        *zap.SugaredLogger
-	repository Repository
+	log             *zap.SugaredLogger
+	repository      Repository
+	storeRepository StoreRepository
 }
 
 func NewService(
 	log *zap.SugaredLogger,
 ) UseCase {
 	return &service{
-		log:        log,
-		repository: NewRepository(log),
+		log:             log,
+		repository:      NewRepository(log),
+		storeRepository: NewStoreRepository(log),
 	}
 }
 
-func (s *service) Create(input *MarketCreateDTO) (*MarketFoundDTO, error) {
+func (s *service) Create(ctx context.Context, input *MarketCreateDTO) (*MarketFoundDTO, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
 	market := NewMarket(
-		input.Name,
+		strings.TrimSpace(input.Name),
 		input.Description,
 	)
 
-	createdMarket, err := s.repository.Create(market)
+	createdMarket, err := s.repository.Create(ctx, market)
 	if err != nil {
 		s.log.Errorw("error creating market", "error", err)
 		return nil, err
 	}
 
-	return &MarketFoundDTO{
-		ID:          createdMarket.ID,
-		Name:        createdMarket.Name,
-		Description: createdMarket.Description,
-		Status:      string(createdMarket.Status),
-	}, nil
+	return toMarketFoundDTO(createdMarket), nil
+}
+
+func (s *service) FindByID(ctx context.Context, id uuid.UUID) (*MarketFoundDTO, error) {
+	market, err := s.findMarket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toMarketFoundDTO(market), nil
+}
+
+func (s *service) Names(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	names, err := s.repository.NamesByIDs(ctx, ids)
+	if err != nil {
+		s.log.Errorw("error finding market names", "error", err)
+		return nil, err
+	}
+	return names, nil
+}
+
+func (s *service) List(ctx context.Context, params *query.Params) (*query.Page[*MarketFoundDTO], error) {
+	markets, total, err := s.repository.List(ctx, params)
+	if err != nil {
+		s.log.Errorw("error listing markets", "error", err)
+		return nil, err
+	}
+
+	page := query.NewPage(markets, total, params, marketSortValue)
+	return query.Map(page, toMarketFoundDTO), nil
+}
+
+func (s *service) Update(ctx context.Context, id uuid.UUID, input *MarketUpdateDTO) (*MarketFoundDTO, error) {
+	market, err := s.findMarket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, ErrNameRequired
+		}
+		market.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		market.Description = *input.Description
+	}
+	if input.Status != nil {
+		switch *input.Status {
+		case MarketStatusActive:
+			market.Activate()
+		case MarketStatusInactive:
+			market.Deactivate()
+		default:
+			return nil, ErrInvalidStatus
+		}
+	}
+
+	updated, err := s.repository.Update(ctx, market)
+	if err != nil {
+		s.log.Errorw("error updating market", "id", id, "error", err)
+		return nil, err
+	}
+
+	return toMarketFoundDTO(updated), nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.findMarket(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		s.log.Errorw("error deleting market", "id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Store methods
+func (s *service) CreateStore(ctx context.Context, marketID uuid.UUID, input *StoreCreateDTO) (*Store, error) {
+	if _, err := s.findMarket(ctx, marketID); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
+	cep, err := normalizeCEP(input.CEP)
+	if err != nil {
+		return nil, err
+	}
+
+	location := geo.Point{Latitude: input.Latitude, Longitude: input.Longitude}
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := input.OpeningHours.Validate(); err != nil {
+		return nil, err
+	}
+
+	store := &Store{
+		ID:           uuid.New(),
+		MarketID:     marketID,
+		Name:         strings.TrimSpace(input.Name),
+		Address:      input.Address,
+		Number:       input.Number,
+		District:     input.District,
+		City:         input.City,
+		StateID:      strings.ToUpper(input.StateID),
+		CEP:          cep,
+		Latitude:     input.Latitude,
+		Longitude:    input.Longitude,
+		OpeningHours: input.OpeningHours,
+		Status:       MarketStatusActive,
+	}
+	if store.OpeningHours == nil {
+		store.OpeningHours = OpeningHours{}
+	}
+
+	saved, err := s.storeRepository.Save(ctx, store)
+	if err != nil {
+		s.log.Errorw("error creating store", "market_id", marketID, "error", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *service) FindStore(ctx context.Context, marketID, storeID uuid.UUID) (*Store, error) {
+	store, err := s.storeRepository.FindByID(ctx, marketID, storeID)
+	if err != nil {
+		s.log.Errorw("error finding store", "market_id", marketID, "id", storeID, "error", err)
+		return nil, err
+	}
+	if store == nil {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
+
+func (s *service) ListStores(ctx context.Context, marketID uuid.UUID) ([]*Store, error) {
+	if _, err := s.findMarket(ctx, marketID); err != nil {
+		return nil, err
+	}
+
+	stores, err := s.storeRepository.ListByMarket(ctx, marketID)
+	if err != nil {
+		s.log.Errorw("error listing stores", "market_id", marketID, "error", err)
+		return nil, err
+	}
+	return stores, nil
+}
+
+func (s *service) UpdateStore(ctx context.Context, marketID, storeID uuid.UUID, input *StoreUpdateDTO) (*Store, error) {
+	store, err := s.FindStore(ctx, marketID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update only provided fields
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, ErrNameRequired
+		}
+		store.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Address != nil {
+		store.Address = *input.Address
+	}
+	if input.Number != nil {
+		store.Number = input.Number
+	}
+	if input.District != nil {
+		store.District = input.District
+	}
+	if input.City != nil {
+		store.City = *input.City
+	}
+	if input.StateID != nil {
+		store.StateID = strings.ToUpper(*input.StateID)
+	}
+	if input.CEP != nil {
+		cep, err := normalizeCEP(*input.CEP)
+		if err != nil {
+			return nil, err
+		}
+		store.CEP = cep
+	}
+	if input.Latitude != nil {
+		store.Latitude = *input.Latitude
+	}
+	if input.Longitude != nil {
+		store.Longitude = *input.Longitude
+	}
+	if err := (geo.Point{Latitude: store.Latitude, Longitude: store.Longitude}).Validate(); err != nil {
+		return nil, err
+	}
+	if input.OpeningHours != nil {
+		if err := input.OpeningHours.Validate(); err != nil {
+			return nil, err
+		}
+		store.OpeningHours = input.OpeningHours
+	}
+	if input.Status != nil {
+		if *input.Status != MarketStatusActive && *input.Status != MarketStatusInactive {
+			return nil, ErrInvalidStatus
+		}
+		store.Status = *input.Status
+	}
+
+	updated, err := s.storeRepository.Update(ctx, store)
+	if err != nil {
+		s.log.Errorw("error updating store", "id", storeID, "error", err)
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *service) DeleteStore(ctx context.Context, marketID, storeID uuid.UUID) error {
+	if _, err := s.FindStore(ctx, marketID, storeID); err != nil {
+		return err
+	}
+
+	if err := s.storeRepository.Delete(ctx, storeID); err != nil {
+		s.log.Errorw("error deleting store", "id", storeID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Nearby narrows candidates with a bounding box in SQL and then keeps the
+// stores whose haversine distance is within the radius, closest first
+func (s *service) Nearby(ctx context.Context, filter *NearbyFilterDTO) ([]*NearbyStoreDTO, error) {
+	center := geo.Point{Latitude: filter.Latitude, Longitude: filter.Longitude}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+
+	if filter.RadiusKm == 0 {
+		filter.RadiusKm = defaultRadiusKm
+	}
+	if filter.RadiusKm < 0 || filter.RadiusKm > maxRadiusKm {
+		return nil, ErrInvalidRadius
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNearbyLimit
+	}
+	if filter.Limit > maxNearbyLimit {
+		filter.Limit = maxNearbyLimit
+	}
+
+	candidates, err := s.storeRepository.ListWithin(ctx, geo.Around(center, filter.RadiusKm))
+	if err != nil {
+		s.log.Errorw("error listing nearby stores", "error", err)
+		return nil, err
+	}
+
+	stores := make([]*NearbyStoreDTO, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.DistanceKm = geo.DistanceKm(center, geo.Point{
+			Latitude:  candidate.Latitude,
+			Longitude: candidate.Longitude,
+		})
+		if candidate.DistanceKm <= filter.RadiusKm {
+			stores = append(stores, candidate)
+		}
+	}
+
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].DistanceKm < stores[j].DistanceKm
+	})
+
+	if len(stores) > filter.Limit {
+		stores = stores[:filter.Limit]
+	}
+	return stores, nil
 }
 
-func (s *service) FindByID(id uuid.UUID) (*MarketFoundDTO, error) {
-	market, err := s.repository.FindByID(id)
+func (s *service) findMarket(ctx context.Context, id uuid.UUID) (*market, error) {
+	market, err := s.repository.FindByID(ctx, id)
 	if err != nil {
 		s.log.Errorw("error finding market by ID", "id", id, "error", err)
 		return nil, err
 	}
 	if market == nil {
 		s.log.Warnw("market not found", "id", id)
-		return nil, nil
+		return nil, ErrMarketNotFound
+	}
+	return market, nil
+}
+
+// normalizeCEP strips the mask and keeps the 8 digits of a Brazilian postal code
+func normalizeCEP(cep string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		if r == '-' || r == '.' || r == ' ' {
+			return -1
+		}
+		return 'x'
+	}, cep)
+
+	if len(digits) != 8 || strings.Contains(digits, "x") {
+		return "", ErrInvalidCEP
 	}
+	return digits, nil
+}
+
+func toMarketFoundDTO(market *market) *MarketFoundDTO {
 	return &MarketFoundDTO{
 		ID:          market.ID,
 		Name:        market.Name,
@@ -61,5 +399,5 @@ func (s *service) FindByID(id uuid.UUID) (*MarketFoundDTO, error) {
 		Status:      string(market.Status),
 		CreatedAt:   market.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:   market.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	}
 }
@@ -0,0 +1,255 @@
+package market
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"market/pkg/geo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type StoreRepository interface {
+	FindByID(ctx context.Context, marketID, id uuid.UUID) (*Store, error)
+	ListByMarket(ctx context.Context, marketID uuid.UUID) ([]*Store, error)
+	ListWithin(ctx context.Context, box geo.BoundingBox) ([]*NearbyStoreDTO, error)
+	Save(ctx context.Context, store *Store) (*Store, error)
+	Update(ctx context.Context, store *Store) (*Store, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type storeRepository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+const storeColumns = `s.id, s.market_id, s.name, s.address, s.number, s.district, s.city, s.state_id,
+	s.cep, s.latitude, s.longitude, s.opening_hours, s.status, s.created_at, s.updated_at`
+
+func NewStoreRepository(log *zap.SugaredLogger) StoreRepository {
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO market_stores
+		(id, market_id, name, address, number, district, city, state_id, cep,
+		latitude, longitude, opening_hours, status, created_at, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	RETURNING created_at, updated_at`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create store statement", "error", err)
+	}
+
+	return &storeRepository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (s *storeRepository) FindByID(ctx context.Context, marketID, id uuid.UUID) (*Store, error) {
+	sql := `SELECT ` + storeColumns + `
+	FROM market_stores s
+	WHERE s.id = $1 AND s.market_id = $2 AND s.status != 'deleted' LIMIT 1`
+
+	rows, err := s.db.QueryContext(ctx, sql, id, marketID)
+	if err != nil {
+		s.log.Errorw("error on execute store FindByID", "error", err, "id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			s.log.Errorw("error on scan store FindByID", "error", err, "id", id)
+			return nil, err
+		}
+		return store, nil
+	}
+
+	return nil, nil
+}
+
+func (s *storeRepository) ListByMarket(ctx context.Context, marketID uuid.UUID) ([]*Store, error) {
+	sql := `SELECT ` + storeColumns + `
+	FROM market_stores s
+	WHERE s.market_id = $1 AND s.status != 'deleted'
+	ORDER BY s.name`
+
+	rows, err := s.db.QueryContext(ctx, sql, marketID)
+	if err != nil {
+		s.log.Errorw("error on execute ListByMarket", "error", err, "market_id", marketID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stores := []*Store{}
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			s.log.Errorw("error on scan ListByMarket", "error", err, "market_id", marketID)
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.log.Errorw("error on iterate ListByMarket", "error", err, "market_id", marketID)
+		return nil, err
+	}
+
+	return stores, nil
+}
+
+// ListWithin returns the active stores of active markets inside the box; the
+// exact distance filter is applied by the caller
+func (s *storeRepository) ListWithin(ctx context.Context, box geo.BoundingBox) ([]*NearbyStoreDTO, error) {
+	sql := `SELECT ` + storeColumns + `, m.name
+	FROM market_stores s
+	INNER JOIN markets m ON m.id = s.market_id
+	WHERE s.status = 'active' AND m.status = 'active'
+		AND s.latitude BETWEEN $1 AND $2
+		AND s.longitude BETWEEN $3 AND $4`
+
+	rows, err := s.db.QueryContext(ctx, sql, box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude)
+	if err != nil {
+		s.log.Errorw("error on execute ListWithin", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stores := []*NearbyStoreDTO{}
+	for rows.Next() {
+		var nearby NearbyStoreDTO
+		store := &nearby.Store
+		err = rows.Scan(
+			&store.ID,
+			&store.MarketID,
+			&store.Name,
+			&store.Address,
+			&store.Number,
+			&store.District,
+			&store.City,
+			&store.StateID,
+			&store.CEP,
+			&store.Latitude,
+			&store.Longitude,
+			&store.OpeningHours,
+			&store.Status,
+			&store.CreatedAt,
+			&store.UpdatedAt,
+			&nearby.MarketName,
+		)
+		if err != nil {
+			s.log.Errorw("error on scan ListWithin", "error", err)
+			return nil, err
+		}
+		stores = append(stores, &nearby)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.log.Errorw("error on iterate ListWithin", "error", err)
+		return nil, err
+	}
+
+	return stores, nil
+}
+
+func (s *storeRepository) Save(ctx context.Context, store *Store) (*Store, error) {
+	err := s.db.Stmt(ctx, s.createStatement).QueryRowContext(
+		ctx,
+		store.ID,
+		store.MarketID,
+		store.Name,
+		store.Address,
+		store.Number,
+		store.District,
+		store.City,
+		store.StateID,
+		store.CEP,
+		store.Latitude,
+		store.Longitude,
+		store.OpeningHours,
+		store.Status,
+	).Scan(&store.CreatedAt, &store.UpdatedAt)
+
+	if err != nil {
+		s.log.Errorw("error on save store", "error", err, "market_id", store.MarketID)
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *storeRepository) Update(ctx context.Context, store *Store) (*Store, error) {
+	sql := `UPDATE market_stores SET
+		name = $2, address = $3, number = $4, district = $5, city = $6, state_id = $7, cep = $8,
+		latitude = $9, longitude = $10, opening_hours = $11, status = $12, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING created_at, updated_at`
+
+	err := s.db.QueryRowContext(
+		ctx,
+		sql,
+		store.ID,
+		store.Name,
+		store.Address,
+		store.Number,
+		store.District,
+		store.City,
+		store.StateID,
+		store.CEP,
+		store.Latitude,
+		store.Longitude,
+		store.OpeningHours,
+		store.Status,
+	).Scan(&store.CreatedAt, &store.UpdatedAt)
+
+	if err != nil {
+		s.log.Errorw("error on update store", "error", err, "id", store.ID)
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *storeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE market_stores SET status = 'deleted', updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := s.db.ExecContext(ctx, sql, id)
+	if err != nil {
+		s.log.Errorw("error on delete store", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func scanStore(rows *sql.Rows) (*Store, error) {
+	var store Store
+	err := rows.Scan(
+		&store.ID,
+		&store.MarketID,
+		&store.Name,
+		&store.Address,
+		&store.Number,
+		&store.District,
+		&store.City,
+		&store.StateID,
+		&store.CEP,
+		&store.Latitude,
+		&store.Longitude,
+		&store.OpeningHours,
+		&store.Status,
+		&store.CreatedAt,
+		&store.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
@@ -0,0 +1,24 @@
+package price_history
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PriceHistoryFilterDTO struct {
+	ProductID uuid.UUID
+	MarketID  *uuid.UUID
+	From      time.Time
+	To        time.Time
+	Interval  Interval
+}
+
+type PriceHistoryResponseDTO struct {
+	ProductID uuid.UUID     `json:"product_id"`
+	MarketID  *uuid.UUID    `json:"market_id,omitempty"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Interval  Interval      `json:"interval"`
+	Buckets   []PriceBucket `json:"buckets"`
+}
@@ -0,0 +1,73 @@
+package price_history
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Interval string
+
+const (
+	IntervalHour  Interval = "hour"
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+)
+
+const SourceManual = "manual"
+
+// PriceHistory representa um preço observado de um produto em um mercado
+type PriceHistory struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	MarketID  uuid.UUID `json:"market_id"`
+	// CompanyID is set on manual prices only visible to a company
+	CompanyID        *uuid.UUID `json:"company_id,omitempty"`
+	Price            float64    `json:"price"`
+	PromotionalPrice *float64   `json:"promotional_price,omitempty"`
+	Source           string     `json:"source"`
+	ObservedAt       time.Time  `json:"observed_at"`
+}
+
+func NewPriceHistory(productID, marketID uuid.UUID, price float64, promotionalPrice *float64, source string) *PriceHistory {
+	return &PriceHistory{
+		ID:               uuid.New(),
+		ProductID:        productID,
+		MarketID:         marketID,
+		Price:            price,
+		PromotionalPrice: promotionalPrice,
+		Source:           source,
+		ObservedAt:       time.Now(),
+	}
+}
+
+// PriceBucket agrega os preços observados em um intervalo de tempo
+type PriceBucket struct {
+	Bucket   time.Time `json:"bucket"`
+	MarketID uuid.UUID `json:"market_id"`
+	MinPrice float64   `json:"min_price"`
+	MaxPrice float64   `json:"max_price"`
+	AvgPrice float64   `json:"avg_price"`
+	Samples  int       `json:"samples"`
+}
+
+// IsValid checks if the interval is supported by the aggregation
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalHour, IntervalDay, IntervalWeek, IntervalMonth:
+		return true
+	}
+	return false
+}
+
+// Changed checks if a new observation differs from the last known prices
+func Changed(oldPrice float64, oldPromotionalPrice *float64, newPrice float64, newPromotionalPrice *float64) bool {
+	if oldPrice != newPrice {
+		return true
+	}
+	if (oldPromotionalPrice == nil) != (newPromotionalPrice == nil) {
+		return true
+	}
+	return oldPromotionalPrice != nil && *oldPromotionalPrice != *newPromotionalPrice
+}
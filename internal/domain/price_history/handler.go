@@ -0,0 +1,87 @@
+package price_history
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// GetPriceHistoryHandler godoc
+// @Summary      Histórico de preços do produto
+// @Description  Retorna os preços observados agregados por intervalo (mínimo, máximo e média)
+// @Tags         products
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string	true	"Product ID"
+// @Param        market_id	query		string	false	"Market ID"
+// @Param        from		query		string	false	"Start date (RFC3339 or YYYY-MM-DD), defaults to 30 days ago"
+// @Param        to			query		string	false	"End date (RFC3339 or YYYY-MM-DD), defaults to now"
+// @Param        interval	query		string	false	"hour, day, week or month"	default(day)
+// @Success      200		{object}	PriceHistoryResponseDTO
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /products/{id}/price-history [get]
+func (h *Handler) GetPriceHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid product ID format")
+		return
+	}
+
+	filter := &PriceHistoryFilterDTO{
+		ProductID: productID,
+		Interval:  Interval(r.URL.Query().Get("interval")),
+	}
+
+	if marketIDStr := r.URL.Query().Get("market_id"); marketIDStr != "" {
+		marketID, err := uuid.Parse(marketIDStr)
+		if err != nil {
+			httpx.SendBadRequest(w, "Invalid market ID format")
+			return
+		}
+		filter.MarketID = &marketID
+	}
+
+	if filter.From, err = parseTime(r.URL.Query().Get("from")); err != nil {
+		httpx.SendBadRequest(w, "Invalid from date")
+		return
+	}
+
+	if filter.To, err = parseTime(r.URL.Query().Get("to")); err != nil {
+		httpx.SendBadRequest(w, "Invalid to date")
+		return
+	}
+
+	history, err := h.usecase.GetHistory(r.Context(), filter)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, history)
+}
+
+// parseTime accepts RFC3339 timestamps or plain dates
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,114 @@
+package price_history
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"market/pkg/security"
+
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Save(ctx context.Context, history *PriceHistory) error
+	Aggregate(ctx context.Context, filter *PriceHistoryFilterDTO) ([]PriceBucket, error)
+}
+
+type repository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO price_history
+		(id, product_id, market_id, company_id, price, promotional_price, source, observed_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8);`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &repository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, history *PriceHistory) error {
+	_, err := r.db.Stmt(ctx, r.createStatement).ExecContext(
+		ctx,
+		history.ID,
+		history.ProductID,
+		history.MarketID,
+		history.CompanyID,
+		history.Price,
+		history.PromotionalPrice,
+		history.Source,
+		history.ObservedAt,
+	)
+
+	if err != nil {
+		r.log.Errorw("error on execute Save", "error", err, "product_id", history.ProductID)
+		return err
+	}
+
+	return nil
+}
+
+// Aggregate groups the observed prices of a product by interval and market.
+// The effective price is the promotional price when there is one.
+func (r *repository) Aggregate(ctx context.Context, filter *PriceHistoryFilterDTO) ([]PriceBucket, error) {
+	sql := `SELECT date_trunc($2::text, observed_at) AS bucket, market_id,
+			MIN(COALESCE(promotional_price, price)),
+			MAX(COALESCE(promotional_price, price)),
+			AVG(COALESCE(promotional_price, price)),
+			COUNT(*)
+		FROM price_history
+		WHERE product_id = $1
+			AND observed_at >= $3 AND observed_at < $4
+			AND ($5::uuid IS NULL OR market_id = $5)
+			AND (company_id IS NULL OR company_id = $6)
+		GROUP BY bucket, market_id
+		ORDER BY bucket, market_id`
+
+	rows, err := r.db.QueryContext(ctx, sql, filter.ProductID, string(filter.Interval), filter.From, filter.To, filter.MarketID, security.CompanyID(ctx))
+	if err != nil {
+		r.log.Errorw("error on execute Aggregate", "error", err, "product_id", filter.ProductID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []PriceBucket{}
+	for rows.Next() {
+		var bucket PriceBucket
+		err = rows.Scan(
+			&bucket.Bucket,
+			&bucket.MarketID,
+			&bucket.MinPrice,
+			&bucket.MaxPrice,
+			&bucket.AvgPrice,
+			&bucket.Samples,
+		)
+		if err != nil {
+			r.log.Errorw("error on scan Aggregate", "error", err, "product_id", filter.ProductID)
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error iterating Aggregate", "error", err, "product_id", filter.ProductID)
+		return nil, err
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,89 @@
+package price_history
+
+import (
+	"context"
+	"fmt"
+	"market/internal/domain/product"
+	"market/pkg/apperr"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrProductNotFound = apperr.NotFound("product_not_found", "product not found")
+	ErrInvalidInterval = apperr.Validation("invalid_interval", "interval must be one of hour, day, week or month")
+	ErrInvalidRange    = apperr.Validation("invalid_range", "from must be before to")
+)
+
+type UseCase interface {
+	Record(ctx context.Context, history *PriceHistory) error
+	GetHistory(ctx context.Context, filter *PriceHistoryFilterDTO) (*PriceHistoryResponseDTO, error)
+}
+
+type service struct {
+	log               *zap.SugaredLogger
+	repository        Repository
+	productRepository product.Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:               log,
+		repository:        NewRepository(log),
+		productRepository: product.NewRepository(log),
+	}
+}
+
+func (s *service) Record(ctx context.Context, history *PriceHistory) error {
+	if err := s.repository.Save(ctx, history); err != nil {
+		s.log.Errorw("error recording price history", "error", err, "product_id", history.ProductID)
+		return fmt.Errorf("error recording price history: %w", err)
+	}
+	return nil
+}
+
+func (s *service) GetHistory(ctx context.Context, filter *PriceHistoryFilterDTO) (*PriceHistoryResponseDTO, error) {
+	if filter.Interval == "" {
+		filter.Interval = IntervalDay
+	}
+	if !filter.Interval.IsValid() {
+		return nil, ErrInvalidInterval
+	}
+
+	if filter.To.IsZero() {
+		filter.To = time.Now()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, -30)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, ErrInvalidRange
+	}
+
+	found, err := s.productRepository.FindByID(ctx, filter.ProductID)
+	if err != nil {
+		s.log.Errorw("error finding product for price history", "error", err, "product_id", filter.ProductID)
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrProductNotFound
+	}
+
+	buckets, err := s.repository.Aggregate(ctx, filter)
+	if err != nil {
+		s.log.Errorw("error aggregating price history", "error", err, "product_id", filter.ProductID)
+		return nil, fmt.Errorf("error aggregating price history: %w", err)
+	}
+
+	return &PriceHistoryResponseDTO{
+		ProductID: filter.ProductID,
+		MarketID:  filter.MarketID,
+		From:      filter.From,
+		To:        filter.To,
+		Interval:  filter.Interval,
+		Buckets:   buckets,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"market/pkg/security"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type CategoryRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*ProductCategory, error)
+	List(ctx context.Context, status *CategoryStatus) ([]*ProductCategory, error)
+	Save(ctx context.Context, category *ProductCategory) (*ProductCategory, error)
+	Update(ctx context.Context, category *ProductCategory) (*ProductCategory, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type categoryRepository struct {
+	db                 *database.PostgresDB
+	log                *zap.SugaredLogger
+	createCategoryStmt *sql.Stmt
+}
+
+func NewCategoryRepository(log *zap.SugaredLogger) CategoryRepository {
+	dbInstance := database.GetInstance(log)
+
+	insertCategory := `INSERT INTO categories
+		(id, company_id, name, description, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING created_at, updated_at`
+
+	createCategoryStmt, err := dbInstance.Prepare(insertCategory)
+	if err != nil {
+		log.Errorw("error preparing create category statement", "error", err)
+	}
+
+	return &categoryRepository{
+		db:                 dbInstance,
+		log:                log,
+		createCategoryStmt: createCategoryStmt,
+	}
+}
+
+func (c *categoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*ProductCategory, error) {
+	sql := `SELECT id, company_id, name, description, status, created_at, updated_at
+			FROM categories WHERE id = $1 AND status != 'deleted' AND ` + companyFilter("$2") + ` LIMIT 1`
+
+	rows, err := c.db.QueryContext(ctx, sql, id, security.CompanyID(ctx))
+	if err != nil {
+		c.log.Errorw("error executing FindByID", "error", err, "id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			c.log.Errorw("error scanning category by ID", "error", err, "id", id)
+			return nil, err
+		}
+		return category, nil
+	}
+
+	return nil, nil
+}
+
+func (c *categoryRepository) List(ctx context.Context, status *CategoryStatus) ([]*ProductCategory, error) {
+	sql := `SELECT id, company_id, name, description, status, created_at, updated_at
+			FROM categories
+			WHERE status != 'deleted' AND ($1::text IS NULL OR status = $1) AND ` + companyFilter("$2") + `
+			ORDER BY name`
+
+	var statusFilter *string
+	if status != nil {
+		value := string(*status)
+		statusFilter = &value
+	}
+
+	rows, err := c.db.QueryContext(ctx, sql, statusFilter, security.CompanyID(ctx))
+	if err != nil {
+		c.log.Errorw("error executing List", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*ProductCategory{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			c.log.Errorw("error scanning category list", "error", err)
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.log.Errorw("error iterating category list", "error", err)
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (c *categoryRepository) Save(ctx context.Context, category *ProductCategory) (*ProductCategory, error) {
+	err := c.db.Stmt(ctx, c.createCategoryStmt).QueryRowContext(
+		ctx,
+		category.ID,
+		category.CompanyID,
+		category.Name,
+		category.Description,
+		category.Status,
+	).Scan(&category.CreatedAt, &category.UpdatedAt)
+
+	if err != nil {
+		c.log.Errorw("error saving category", "error", err)
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (c *categoryRepository) Update(ctx context.Context, category *ProductCategory) (*ProductCategory, error) {
+	sql := `UPDATE categories SET
+		name = $2, description = $3, status = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ` + companyFilter("$5") + `
+		RETURNING created_at, updated_at`
+
+	err := c.db.QueryRowContext(
+		ctx,
+		sql,
+		category.ID,
+		category.Name,
+		category.Description,
+		category.Status,
+		security.CompanyID(ctx),
+	).Scan(&category.CreatedAt, &category.UpdatedAt)
+
+	if err != nil {
+		c.log.Errorw("error updating category", "error", err, "id", category.ID)
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Delete marks the category as deleted, products keep pointing to it
+func (c *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE categories SET status = 'deleted', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ` + companyFilter("$2")
+
+	_, err := c.db.ExecContext(ctx, sql, id, security.CompanyID(ctx))
+	if err != nil {
+		c.log.Errorw("error deleting category", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+// companyFilter keeps the categories shared by every company and the ones of
+// the caller company, param is the placeholder holding the company ID
+func companyFilter(param string) string {
+	return `(company_id IS NULL OR company_id = ` + param + `)`
+}
+
+func scanCategory(rows *sql.Rows) (*ProductCategory, error) {
+	var category ProductCategory
+	err := rows.Scan(
+		&category.ID,
+		&category.CompanyID,
+		&category.Name,
+		&category.Description,
+		&category.Status,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
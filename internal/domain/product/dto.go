@@ -9,4 +9,25 @@ type ProductCreateDTO struct {
 	CategoryID uuid.UUID `json:"category_id" validate:"required"`
 	Name       string    `json:"name" validate:"required,min=3,max=100"`
 	ImageURL   *string   `json:"image_url,omitempty" validate:"omitempty,url,max=180"`
+	Unit       *string   `json:"unit,omitempty" validate:"omitempty,max=20"`
+}
+
+type ProductUpdateDTO struct {
+	CategoryID *uuid.UUID     `json:"category_id,omitempty"`
+	Name       *string        `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
+	ImageURL   *string        `json:"image_url,omitempty" validate:"omitempty,url,max=180"`
+	Unit       *string        `json:"unit,omitempty" validate:"omitempty,max=20"`
+	Status     *ProductStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+}
+
+// Category DTOs
+type CategoryCreateDTO struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+type CategoryUpdateDTO struct {
+	Name        *string         `json:"name,omitempty" validate:"omitempty,max=100"`
+	Description *string         `json:"description,omitempty" validate:"omitempty,max=255"`
+	Status      *CategoryStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
 }
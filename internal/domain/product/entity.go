@@ -1,6 +1,7 @@
 package product
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,14 +15,22 @@ const (
 	ProductStatusDeleted  ProductStatus = "deleted"
 )
 
+type CategoryStatus string
+
+const (
+	CategoryStatusActive   CategoryStatus = "active"
+	CategoryStatusInactive CategoryStatus = "inactive"
+	CategoryStatusDeleted  CategoryStatus = "deleted"
+)
+
 type ProductCategory struct {
-	ID          uuid.UUID `json:"id"`
-	CompanyID   uuid.UUID `json:"company_id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID      `json:"id"`
+	CompanyID   *uuid.UUID     `json:"company_id,omitempty"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Status      CategoryStatus `json:"status"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
 // Product representa um produto no sistema
@@ -32,12 +41,43 @@ type Product struct {
 	Name       string        `json:"name"`
 	Unit       *string       `json:"unit,omitempty"`
 	Status     ProductStatus `json:"status"`
-	CreatedAt  time.Time     `json:"created_at"`
-	UpdatedAt  time.Time     `json:"updated_at"`
+	// CuratedFields lists the fields edited by a curator, kept on sync
+	CuratedFields []string  `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// Fields the providers also fill, tracked once a curator edits them
+const (
+	FieldName       = "name"
+	FieldImageURL   = "image_url"
+	FieldCategoryID = "category_id"
+)
+
 // ProductWithCategory representa um produto com informações da categoria
 type ProductWithCategory struct {
 	Product
 	Category *ProductCategory `json:"category,omitempty"`
 }
+
+// IsValid checks if the status can be set through the API
+func (s ProductStatus) IsValid() bool {
+	return s == ProductStatusActive || s == ProductStatusInactive
+}
+
+// IsValid checks if the status can be set through the API
+func (s CategoryStatus) IsValid() bool {
+	return s == CategoryStatusActive || s == CategoryStatusInactive
+}
+
+// Curated tells whether a curator edited the field
+func (p *Product) Curated(field string) bool {
+	return slices.Contains(p.CuratedFields, field)
+}
+
+// MarkCurated records that a curator edited the field
+func (p *Product) MarkCurated(field string) {
+	if !p.Curated(field) {
+		p.CuratedFields = append(p.CuratedFields, field)
+	}
+}
@@ -1,7 +1,11 @@
 package product
 
 import (
+	"market/pkg/httpx"
+	"market/pkg/query"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 type Handler struct {
@@ -14,38 +18,302 @@ func NewHandler(uc UseCase) *Handler {
 	}
 }
 
+// CreateProductHandler godoc
+// @Summary      Criar produto
+// @Description  Cadastra um novo produto em uma categoria existente
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		ProductCreateDTO	true	"Product data"
+// @Success      201		{object}	ProductWithCategory
+// @Failure      400		{object}	httpx.Problem
+// @Router       /products [post]
+func (h *Handler) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var dto ProductCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	product, err := h.usecase.CreateProduct(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, product)
+}
+
+// ListProductsHandler godoc
+// @Summary      Listar produtos
+// @Description  Lista produtos com paginação por página ou cursor, filtros e ordenação
+// @Tags         products
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        category_id	query		string	false	"Category ID"
+// @Param        status			query		string	false	"Status (active, inactive)"
+// @Param        name			query		string	false	"Exact name"
+// @Param        created_at_gte	query		string	false	"Created from (YYYY-MM-DD or RFC 3339)"
+// @Param        created_at_lte	query		string	false	"Created until (YYYY-MM-DD or RFC 3339)"
+// @Param        sort			query		string	false	"Sort fields, - for descending (name, created_at, updated_at)"
+// @Param        page			query		int		false	"Page (default 1)"
+// @Param        page_size		query		int		false	"Page size (default 20, max 100)"
+// @Param        cursor			query		string	false	"next_cursor of the previous page"
+// @Success      200			{object}	query.Page[ProductWithCategory]
+// @Failure      400			{object}	httpx.Problem
+// @Router       /products [get]
+func (h *Handler) ListProductsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params, err := query.Parse(r.URL.Query(), productQuery)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	products, err := h.usecase.ListProducts(r.Context(), params)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, products)
+}
+
+// GetProductHandler godoc
+// @Summary      Obter produto
+// @Description  Retorna um produto com sua categoria
+// @Tags         products
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"Product ID"
+// @Success      200	{object}	ProductWithCategory
+// @Failure      404	{object}	httpx.Problem
+// @Router       /products/{id} [get]
 func (h *Handler) GetProductHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// // Get user from context
-	// userCtx, err := security.GetUser(r.Context())
-	// if err != nil {
-	// 	httpx.SendUnauthorized(w, "Authentication required")
-	// 	return
-	// }
-
-	// Get product ID from URL path
-	// idStr := r.PathValue("id")
-
-	// productID, err := uuid.Parse(idStr)
-	// if err != nil {
-	// 	httpx.SendBadRequest(w, "Invalid product ID format")
-	// 	return
-	// }
-
-	// product, err := h.usecase.GetProduct(productID)
-	// if err != nil {
-	// 	if err.Error() == "product not found" {
-	// 		httpx.SendNotFound(w, "Product not found")
-	// 		return
-	// 	}
-	// 	if err.Error() == "access denied" {
-	// 		httpx.SendForbidden(w, "Access denied")
-	// 		return
-	// 	}
-	// 	httpx.SendInternalServerError(w, err.Error(), err)
-	// 	return
-	// }
-
-	// json.NewEncoder(w).Encode(product)
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid product ID format")
+		return
+	}
+
+	product, err := h.usecase.GetProduct(r.Context(), productID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, product)
+}
+
+// UpdateProductHandler godoc
+// @Summary      Atualizar produto
+// @Description  Atualiza somente os campos informados do produto
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string				true	"Product ID"
+// @Param        request	body		ProductUpdateDTO	true	"Product data"
+// @Success      200		{object}	ProductWithCategory
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /products/{id} [put]
+func (h *Handler) UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid product ID format")
+		return
+	}
+
+	var dto ProductUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	product, err := h.usecase.UpdateProduct(r.Context(), productID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, product)
+}
+
+// DeleteProductHandler godoc
+// @Summary      Remover produto
+// @Description  Marca o produto como removido (soft delete)
+// @Tags         products
+// @Security     ApiKeyAuth
+// @Param        id		path	string	true	"Product ID"
+// @Success      204	"No Content"
+// @Failure      404	{object}	httpx.Problem
+// @Router       /products/{id} [delete]
+func (h *Handler) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	productID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid product ID format")
+		return
+	}
+
+	if err := h.usecase.DeleteProduct(r.Context(), productID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateCategoryHandler godoc
+// @Summary      Criar categoria
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		CategoryCreateDTO	true	"Category data"
+// @Success      201		{object}	ProductCategory
+// @Failure      400		{object}	httpx.Problem
+// @Router       /categories [post]
+func (h *Handler) CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var dto CategoryCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	category, err := h.usecase.CreateCategory(r.Context(), &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, category)
+}
+
+// ListCategoriesHandler godoc
+// @Summary      Listar categorias
+// @Tags         categories
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        status	query		string	false	"Status (active, inactive)"
+// @Success      200	{array}		ProductCategory
+// @Failure      400	{object}	httpx.Problem
+// @Router       /categories [get]
+func (h *Handler) ListCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var status *CategoryStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		s := CategoryStatus(value)
+		status = &s
+	}
+
+	categories, err := h.usecase.ListCategories(r.Context(), status)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, categories)
+}
+
+// GetCategoryHandler godoc
+// @Summary      Obter categoria
+// @Tags         categories
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"Category ID"
+// @Success      200	{object}	ProductCategory
+// @Failure      404	{object}	httpx.Problem
+// @Router       /categories/{id} [get]
+func (h *Handler) GetCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	categoryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid category ID format")
+		return
+	}
+
+	category, err := h.usecase.GetCategory(r.Context(), categoryID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, category)
+}
+
+// UpdateCategoryHandler godoc
+// @Summary      Atualizar categoria
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string				true	"Category ID"
+// @Param        request	body		CategoryUpdateDTO	true	"Category data"
+// @Success      200		{object}	ProductCategory
+// @Failure      400		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /categories/{id} [put]
+func (h *Handler) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	categoryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid category ID format")
+		return
+	}
+
+	var dto CategoryUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	category, err := h.usecase.UpdateCategory(r.Context(), categoryID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, category)
+}
+
+// DeleteCategoryHandler godoc
+// @Summary      Remover categoria
+// @Description  Marca a categoria como removida (soft delete)
+// @Tags         categories
+// @Security     ApiKeyAuth
+// @Param        id		path	string	true	"Category ID"
+// @Success      204	"No Content"
+// @Failure      404	{object}	httpx.Problem
+// @Router       /categories/{id} [delete]
+func (h *Handler) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	categoryID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid category ID format")
+		return
+	}
+
+	if err := h.usecase.DeleteCategory(r.Context(), categoryID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
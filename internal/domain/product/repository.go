@@ -1,16 +1,25 @@
 package product
 
 import (
+	"context"
 	"database/sql"
 	"market/pkg/database"
+	"market/pkg/query"
+	"market/pkg/security"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
-	FindByID(id uuid.UUID) (*Product, error)
-	Save(product *Product) (*Product, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Product, error)
+	FindWithCategoryByID(ctx context.Context, id uuid.UUID) (*ProductWithCategory, error)
+	// List returns a page of products, with one extra row, and the total matching the filters
+	List(ctx context.Context, params *query.Params) ([]*ProductWithCategory, int, error)
+	Save(ctx context.Context, product *Product) (*Product, error)
+	Update(ctx context.Context, product *Product) (*Product, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type productRepository struct {
@@ -19,13 +28,46 @@ type productRepository struct {
 	createProductStmt *sql.Stmt
 }
 
+const productWithCategoryColumns = `p.id, p.category_id, p.image_url, p.name, p.unit, p.status, p.created_at, p.updated_at,
+	c.id, c.company_id, c.name, c.description, c.status, c.created_at, c.updated_at`
+
+// productQuery is what clients may filter and sort product listings by
+var productQuery = &query.Schema{
+	Fields: map[string]query.Field{
+		"id":          {Column: "p.id", Type: query.UUID},
+		"name":        {Column: "p.name", Type: query.String, Ops: []query.Op{query.Eq}, Sortable: true},
+		"category_id": {Column: "p.category_id", Type: query.UUID, Ops: []query.Op{query.Eq}},
+		"status":      {Column: "p.status", Type: query.String, Ops: []query.Op{query.Eq}, Values: []string{"active", "inactive"}},
+		"created_at":  {Column: "p.created_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+		"updated_at":  {Column: "p.updated_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: []query.Sort{{Field: "name"}},
+	DefaultSize: 20,
+	MaxSize:     100,
+}
+
+// productSortValue feeds the next cursor of product listings
+func productSortValue(product *ProductWithCategory, field string) any {
+	switch field {
+	case "name":
+		return product.Name
+	case "created_at":
+		return product.CreatedAt
+	case "updated_at":
+		return product.UpdatedAt
+	default:
+		return product.ID
+	}
+}
+
 func NewRepository(log *zap.SugaredLogger) Repository {
 	dbInstance := database.GetInstance(log)
 
 	// Product statements
 	insertProduct := `INSERT INTO products 
-		(category_id, image_url, name, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		(category_id, image_url, name, unit, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING id, created_at, updated_at`
 
 	// Prepare statements
@@ -41,11 +83,11 @@ func NewRepository(log *zap.SugaredLogger) Repository {
 	}
 }
 
-func (p *productRepository) FindByID(id uuid.UUID) (*Product, error) {
-	sql := `SELECT id, category_id, image_url, name, status, created_at, updated_at
+func (p *productRepository) FindByID(ctx context.Context, id uuid.UUID) (*Product, error) {
+	sql := `SELECT id, category_id, image_url, name, unit, status, curated_fields, created_at, updated_at
 			FROM products WHERE id = $1 AND status != 'deleted' LIMIT 1`
 
-	rows, err := p.db.Query(sql, id)
+	rows, err := p.db.QueryContext(ctx, sql, id)
 	if err != nil {
 		p.log.Errorw("error executing FindByID", "error", err, "id", id)
 		return nil, err
@@ -59,7 +101,9 @@ func (p *productRepository) FindByID(id uuid.UUID) (*Product, error) {
 			&product.CategoryID,
 			&product.ImageURL,
 			&product.Name,
+			&product.Unit,
 			&product.Status,
+			pq.Array(&product.CuratedFields),
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		)
@@ -75,11 +119,82 @@ func (p *productRepository) FindByID(id uuid.UUID) (*Product, error) {
 	return nil, nil
 }
 
-func (p *productRepository) Save(product *Product) (*Product, error) {
-	err := p.createProductStmt.QueryRow(
+func (p *productRepository) FindWithCategoryByID(ctx context.Context, id uuid.UUID) (*ProductWithCategory, error) {
+	sql := `SELECT ` + productWithCategoryColumns + `
+			FROM products p
+			LEFT JOIN categories c ON c.id = p.category_id AND c.status != 'deleted'
+				AND (c.company_id IS NULL OR c.company_id = $2)
+			WHERE p.id = $1 AND p.status != 'deleted' LIMIT 1`
+
+	rows, err := p.db.QueryContext(ctx, sql, id, security.CompanyID(ctx))
+	if err != nil {
+		p.log.Errorw("error executing FindWithCategoryByID", "error", err, "id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		product, err := scanProductWithCategory(rows)
+		if err != nil {
+			p.log.Errorw("error scanning product with category by ID", "error", err, "id", id)
+			return nil, err
+		}
+
+		return product, nil
+	}
+
+	return nil, nil
+}
+
+func (p *productRepository) List(ctx context.Context, params *query.Params) ([]*ProductWithCategory, int, error) {
+	b := params.NewBuilder()
+	where := `WHERE p.status != 'deleted'` + b.Filters()
+
+	var total int
+	countSQL := `SELECT COUNT(*) FROM products p ` + where
+	if err := p.db.QueryRowContext(ctx, countSQL, b.Args()...).Scan(&total); err != nil {
+		p.log.Errorw("error counting products", "error", err)
+		return nil, 0, err
+	}
+
+	listSQL := `SELECT ` + productWithCategoryColumns + `
+			FROM products p
+			LEFT JOIN categories c ON c.id = p.category_id AND c.status != 'deleted'
+				AND (c.company_id IS NULL OR c.company_id = ` + b.Arg(security.CompanyID(ctx)) + `)
+			` + where + b.Page()
+
+	rows, err := p.db.QueryContext(ctx, listSQL, b.Args()...)
+	if err != nil {
+		p.log.Errorw("error executing List", "error", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := []*ProductWithCategory{}
+	for rows.Next() {
+		product, err := scanProductWithCategory(rows)
+		if err != nil {
+			p.log.Errorw("error scanning product list", "error", err)
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		p.log.Errorw("error iterating product list", "error", err)
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+func (p *productRepository) Save(ctx context.Context, product *Product) (*Product, error) {
+	err := p.db.Stmt(ctx, p.createProductStmt).QueryRowContext(
+		ctx,
 		product.CategoryID,
 		product.ImageURL,
 		product.Name,
+		product.Unit,
 		product.Status,
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
 
@@ -90,3 +205,92 @@ func (p *productRepository) Save(product *Product) (*Product, error) {
 
 	return product, nil
 }
+
+func (p *productRepository) Update(ctx context.Context, product *Product) (*Product, error) {
+	sql := `UPDATE products SET
+		category_id = $2, image_url = $3, name = $4, unit = $5, status = $6, curated_fields = $7,
+		updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_at, updated_at`
+
+	err := p.db.QueryRowContext(
+		ctx,
+		sql,
+		product.ID,
+		product.CategoryID,
+		product.ImageURL,
+		product.Name,
+		product.Unit,
+		product.Status,
+		pq.Array(product.CuratedFields),
+	).Scan(&product.CreatedAt, &product.UpdatedAt)
+
+	if err != nil {
+		p.log.Errorw("error updating product", "error", err, "id", product.ID)
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Delete marks the product as deleted keeping its prices history
+func (p *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE products SET status = 'deleted', updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := p.db.ExecContext(ctx, sql, id)
+	if err != nil {
+		p.log.Errorw("error deleting product", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func scanProductWithCategory(rows *sql.Rows) (*ProductWithCategory, error) {
+	var product ProductWithCategory
+	// Category columns are all NULL when the product has no category
+	var category struct {
+		ID          *uuid.UUID
+		CompanyID   *uuid.UUID
+		Name        sql.NullString
+		Description *string
+		Status      sql.NullString
+		CreatedAt   sql.NullTime
+		UpdatedAt   sql.NullTime
+	}
+
+	err := rows.Scan(
+		&product.ID,
+		&product.CategoryID,
+		&product.ImageURL,
+		&product.Name,
+		&product.Unit,
+		&product.Status,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&category.ID,
+		&category.CompanyID,
+		&category.Name,
+		&category.Description,
+		&category.Status,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.ID != nil {
+		product.Category = &ProductCategory{
+			ID:          *category.ID,
+			CompanyID:   category.CompanyID,
+			Name:        category.Name.String,
+			Description: category.Description,
+			Status:      CategoryStatus(category.Status.String),
+			CreatedAt:   category.CreatedAt.Time,
+			UpdatedAt:   category.UpdatedAt.Time,
+		}
+	}
+
+	return &product, nil
+}
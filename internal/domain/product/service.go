@@ -1,54 +1,303 @@
 package product
 
 import (
+	"context"
 	"fmt"
 	"market/internal/domain/market"
+	"market/internal/domain/user"
+	"market/pkg/apperr"
+	"market/pkg/query"
+	"market/pkg/security"
+	"strings"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrProductNotFound  = apperr.NotFound("product_not_found", "product not found")
+	ErrCategoryNotFound = apperr.NotFound("category_not_found", "category not found")
+	ErrSharedCategory   = apperr.Forbidden("shared_category", "only admins can change categories shared by every company")
+	ErrPrivateCategory  = apperr.Validation("private_category", "products can only use categories shared by every company")
+	ErrInvalidStatus    = apperr.Validation("invalid_status", "status must be active or inactive")
+	ErrNameRequired     = apperr.Validation("name_required", "name is required")
+)
+
 type UseCase interface {
-	CreateProduct(dto *ProductCreateDTO) error
+	CreateProduct(ctx context.Context, dto *ProductCreateDTO) (*ProductWithCategory, error)
+	GetProduct(ctx context.Context, id uuid.UUID) (*ProductWithCategory, error)
+	ListProducts(ctx context.Context, params *query.Params) (*query.Page[*ProductWithCategory], error)
+	UpdateProduct(ctx context.Context, id uuid.UUID, dto *ProductUpdateDTO) (*ProductWithCategory, error)
+	DeleteProduct(ctx context.Context, id uuid.UUID) error
+
+	CreateCategory(ctx context.Context, dto *CategoryCreateDTO) (*ProductCategory, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*ProductCategory, error)
+	ListCategories(ctx context.Context, status *CategoryStatus) ([]*ProductCategory, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, dto *CategoryUpdateDTO) (*ProductCategory, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
 }
 
 type service struct {
-	log           *zap.SugaredLogger
-	repository    Repository
-	marketService market.UseCase
+	log                *zap.SugaredLogger
+	repository         Repository
+	categoryRepository CategoryRepository
+	marketService      market.UseCase
 }
 
 func NewService(
 	log *zap.SugaredLogger,
 ) UseCase {
 	return &service{
-		log:           log,
-		repository:    NewRepository(log),
-		marketService: market.NewService(log),
+		log:                log,
+		repository:         NewRepository(log),
+		categoryRepository: NewCategoryRepository(log),
+		marketService:      market.NewService(log),
 	}
 }
 
 // Product methods
-func (s *service) CreateProduct(dto *ProductCreateDTO) error {
+func (s *service) CreateProduct(ctx context.Context, dto *ProductCreateDTO) (*ProductWithCategory, error) {
 	// Basic validation
-	if dto.Name == "" {
-		return fmt.Errorf("product name is required")
+	if strings.TrimSpace(dto.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
+	if err := s.ensureCategory(ctx, dto.CategoryID); err != nil {
+		return nil, err
 	}
 
 	// Create product entity
 	product := &Product{
 		ID:         uuid.New(),
 		CategoryID: &dto.CategoryID,
-		Name:       dto.Name,
+		Name:       strings.TrimSpace(dto.Name),
 		ImageURL:   dto.ImageURL,
+		Unit:       dto.Unit,
 		Status:     ProductStatusActive,
 	}
 
 	// Save to repository
-	_, err := s.repository.Save(product)
+	saved, err := s.repository.Save(ctx, product)
 	if err != nil {
 		s.log.Errorw("error saving product", "error", err)
-		return fmt.Errorf("error saving product: %w", err)
+		return nil, fmt.Errorf("error saving product: %w", err)
+	}
+
+	return s.GetProduct(ctx, saved.ID)
+}
+
+func (s *service) GetProduct(ctx context.Context, id uuid.UUID) (*ProductWithCategory, error) {
+	product, err := s.repository.FindWithCategoryByID(ctx, id)
+	if err != nil {
+		s.log.Errorw("error finding product", "error", err, "id", id)
+		return nil, fmt.Errorf("error finding product: %w", err)
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+func (s *service) ListProducts(ctx context.Context, params *query.Params) (*query.Page[*ProductWithCategory], error) {
+	products, total, err := s.repository.List(ctx, params)
+	if err != nil {
+		s.log.Errorw("error listing products", "error", err)
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+
+	return query.NewPage(products, total, params, productSortValue), nil
+}
+
+func (s *service) UpdateProduct(ctx context.Context, id uuid.UUID, dto *ProductUpdateDTO) (*ProductWithCategory, error) {
+	product, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		s.log.Errorw("error finding product for update", "error", err, "id", id)
+		return nil, fmt.Errorf("error finding product: %w", err)
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	// Update only provided fields
+	if dto.CategoryID != nil {
+		if err := s.ensureCategory(ctx, *dto.CategoryID); err != nil {
+			return nil, err
+		}
+		product.CategoryID = dto.CategoryID
+		product.MarkCurated(FieldCategoryID)
+	}
+	if dto.Name != nil {
+		if strings.TrimSpace(*dto.Name) == "" {
+			return nil, ErrNameRequired
+		}
+		product.Name = strings.TrimSpace(*dto.Name)
+		product.MarkCurated(FieldName)
+	}
+	if dto.ImageURL != nil {
+		product.ImageURL = dto.ImageURL
+		product.MarkCurated(FieldImageURL)
+	}
+	if dto.Unit != nil {
+		product.Unit = dto.Unit
+	}
+	if dto.Status != nil {
+		if !dto.Status.IsValid() {
+			return nil, ErrInvalidStatus
+		}
+		product.Status = *dto.Status
+	}
+
+	if _, err := s.repository.Update(ctx, product); err != nil {
+		s.log.Errorw("error updating product", "error", err, "id", id)
+		return nil, fmt.Errorf("error updating product: %w", err)
+	}
+
+	return s.GetProduct(ctx, id)
+}
+
+func (s *service) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	product, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		s.log.Errorw("error finding product for deletion", "error", err, "id", id)
+		return fmt.Errorf("error finding product: %w", err)
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		s.log.Errorw("error deleting product", "error", err, "id", id)
+		return fmt.Errorf("error deleting product: %w", err)
+	}
+
+	return nil
+}
+
+// Category methods
+func (s *service) CreateCategory(ctx context.Context, dto *CategoryCreateDTO) (*ProductCategory, error) {
+	if strings.TrimSpace(dto.Name) == "" {
+		return nil, ErrNameRequired
+	}
+
+	// Admins maintain the shared catalog, curators the categories of their company
+	var companyID *uuid.UUID
+	if !isAdmin(ctx) {
+		id := security.CompanyID(ctx)
+		companyID = &id
+	}
+
+	category := &ProductCategory{
+		ID:          uuid.New(),
+		CompanyID:   companyID,
+		Name:        strings.TrimSpace(dto.Name),
+		Description: dto.Description,
+		Status:      CategoryStatusActive,
+	}
+
+	saved, err := s.categoryRepository.Save(ctx, category)
+	if err != nil {
+		s.log.Errorw("error saving category", "error", err)
+		return nil, fmt.Errorf("error saving category: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (s *service) GetCategory(ctx context.Context, id uuid.UUID) (*ProductCategory, error) {
+	category, err := s.categoryRepository.FindByID(ctx, id)
+	if err != nil {
+		s.log.Errorw("error finding category", "error", err, "id", id)
+		return nil, fmt.Errorf("error finding category: %w", err)
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	return category, nil
+}
+
+func (s *service) ListCategories(ctx context.Context, status *CategoryStatus) ([]*ProductCategory, error) {
+	if status != nil && !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	categories, err := s.categoryRepository.List(ctx, status)
+	if err != nil {
+		s.log.Errorw("error listing categories", "error", err)
+		return nil, fmt.Errorf("error listing categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (s *service) UpdateCategory(ctx context.Context, id uuid.UUID, dto *CategoryUpdateDTO) (*ProductCategory, error) {
+	category, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category.CompanyID == nil && !isAdmin(ctx) {
+		return nil, ErrSharedCategory
+	}
+
+	// Update only provided fields
+	if dto.Name != nil {
+		if strings.TrimSpace(*dto.Name) == "" {
+			return nil, ErrNameRequired
+		}
+		category.Name = strings.TrimSpace(*dto.Name)
+	}
+	if dto.Description != nil {
+		category.Description = dto.Description
+	}
+	if dto.Status != nil {
+		if !dto.Status.IsValid() {
+			return nil, ErrInvalidStatus
+		}
+		category.Status = *dto.Status
+	}
+
+	updated, err := s.categoryRepository.Update(ctx, category)
+	if err != nil {
+		s.log.Errorw("error updating category", "error", err, "id", id)
+		return nil, fmt.Errorf("error updating category: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (s *service) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	category, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if category.CompanyID == nil && !isAdmin(ctx) {
+		return ErrSharedCategory
+	}
+
+	if err := s.categoryRepository.Delete(ctx, id); err != nil {
+		s.log.Errorw("error deleting category", "error", err, "id", id)
+		return fmt.Errorf("error deleting category: %w", err)
+	}
+
+	return nil
+}
+
+func isAdmin(ctx context.Context) bool {
+	userAuth, err := security.GetUser(ctx)
+	return err == nil && user.Role(userAuth.Role) == user.RoleAdmin
+}
+
+// ensureCategory checks that the category exists, is not deleted and is shared.
+// Products are seen by every company, so a company category would leak its
+// name to the others and be unresolvable for them.
+func (s *service) ensureCategory(ctx context.Context, id uuid.UUID) error {
+	category, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if category.CompanyID != nil {
+		return ErrPrivateCategory
 	}
 	return nil
 }
@@ -6,11 +6,12 @@ import (
 
 // ProductMarket DTOs
 type ProductMarketCreateDTO struct {
-	ProviderID       *string   `json:"provider_id,omitempty"`
-	ProductID        uuid.UUID `json:"product_id" validate:"required"`
-	MarketID         uuid.UUID `json:"market_id" validate:"required"`
-	Price            float64   `json:"price" validate:"required,gt=0"`
-	PromotionalPrice *float64  `json:"promotional_price,omitempty" validate:"omitempty,gt=0"`
+	ProviderID       *string    `json:"provider_id,omitempty"`
+	ProductID        uuid.UUID  `json:"product_id" validate:"required"`
+	MarketID         uuid.UUID  `json:"market_id" validate:"required"`
+	StoreID          *uuid.UUID `json:"store_id,omitempty"`
+	Price            float64    `json:"price" validate:"required,gt=0"`
+	PromotionalPrice *float64   `json:"promotional_price,omitempty" validate:"omitempty,gt=0"`
 }
 
 type ProductMarketResponseDTO struct {
@@ -18,6 +19,8 @@ type ProductMarketResponseDTO struct {
 	ProviderID       *string             `json:"provider_id,omitempty"`
 	ProductID        uuid.UUID           `json:"product_id"`
 	MarketID         uuid.UUID           `json:"market_id"`
+	StoreID          *uuid.UUID          `json:"store_id,omitempty"`
+	CompanyID        *uuid.UUID          `json:"company_id,omitempty"`
 	Price            float64             `json:"price"`
 	PromotionalPrice *float64            `json:"promotional_price,omitempty"`
 	Status           ProductMarketStatus `json:"status"`
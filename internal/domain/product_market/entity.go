@@ -16,13 +16,24 @@ const (
 
 // ProductMarket representa a relação entre produto e mercado com preços
 type ProductMarket struct {
-	ID               uuid.UUID           `json:"id"`
-	ProviderID       *string             `json:"provider_id,omitempty"`
-	ProductID        uuid.UUID           `json:"product_id"`
-	MarketID         uuid.UUID           `json:"market_id"`
+	ID         uuid.UUID  `json:"id"`
+	ProviderID *string    `json:"provider_id,omitempty"`
+	ProductID  uuid.UUID  `json:"product_id"`
+	MarketID   uuid.UUID  `json:"market_id"`
+	StoreID    *uuid.UUID `json:"store_id,omitempty"`
+	// CompanyID is set on prices only visible to a company, synced prices are shared
+	CompanyID        *uuid.UUID          `json:"company_id,omitempty"`
 	Price            float64             `json:"price"`
 	PromotionalPrice *float64            `json:"promotional_price,omitempty"`
 	Status           ProductMarketStatus `json:"status"`
 	CreatedAt        time.Time           `json:"created_at"`
 	UpdatedAt        time.Time           `json:"updated_at"`
 }
+
+// EffectivePrice returns the promotional price when there is one, otherwise the regular price
+func (p *ProductMarket) EffectivePrice() float64 {
+	if p.PromotionalPrice != nil && *p.PromotionalPrice > 0 {
+		return *p.PromotionalPrice
+	}
+	return p.Price
+}
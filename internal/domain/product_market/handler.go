@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"market/pkg/httpx"
+	"market/pkg/query"
 )
 
 type Handler struct {
@@ -21,14 +22,14 @@ func (h *Handler) CreateProductMarketHandler(w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Content-Type", "application/json")
 
 	var dto ProductMarketCreateDTO
-	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		httpx.SendBadRequest(w, "Invalid JSON format")
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
 		return
 	}
 
-	productMarket, err := h.usecase.CreateProductMarket(&dto)
+	productMarket, err := h.usecase.CreateProductMarket(r.Context(), &dto)
 	if err != nil {
-		httpx.SendInternalServerError(w, err.Error(), err)
+		httpx.SendError(w, err)
 		return
 	}
 
@@ -36,9 +37,45 @@ func (h *Handler) CreateProductMarketHandler(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(productMarket)
 }
 
-func (h *Handler) GetProductMarketsByProviderIDHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+// ListProductMarketsHandler godoc
+// @Summary      Listar preços
+// @Description  Lista os preços dos produtos nos mercados com paginação por página ou cursor, filtros e ordenação
+// @Tags         product-markets
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        product_id		query		string	false	"Product ID"
+// @Param        market_id		query		string	false	"Market ID"
+// @Param        store_id		query		string	false	"Store ID"
+// @Param        provider_id	query		string	false	"Provider ID"
+// @Param        status			query		string	false	"Status (active, inactive)"
+// @Param        price_gte		query		number	false	"Minimum price"
+// @Param        price_lte		query		number	false	"Maximum price"
+// @Param        sort			query		string	false	"Sort fields, - for descending (price, created_at, updated_at)"
+// @Param        page			query		int		false	"Page (default 1)"
+// @Param        page_size		query		int		false	"Page size (default 50, max 200)"
+// @Param        cursor			query		string	false	"next_cursor of the previous page"
+// @Success      200			{object}	query.Page[ProductMarket]
+// @Failure      400			{object}	httpx.Problem
+// @Router       /product-markets [get]
+func (h *Handler) ListProductMarketsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := query.Parse(r.URL.Query(), productMarketQuery)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	productMarkets, err := h.usecase.List(r.Context(), params)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, productMarkets)
+}
 
+// GetProductMarketsByProviderIDHandler lists the prices of a provider product,
+// accepting the same filters, sort and pagination as ListProductMarketsHandler
+func (h *Handler) GetProductMarketsByProviderIDHandler(w http.ResponseWriter, r *http.Request) {
 	// Get provider ID from URL path
 	providerID := r.PathValue("provider_id")
 	if providerID == "" {
@@ -46,11 +83,18 @@ func (h *Handler) GetProductMarketsByProviderIDHandler(w http.ResponseWriter, r
 		return
 	}
 
-	productMarkets, err := h.usecase.FindByProviderID(providerID)
+	params, err := query.Parse(r.URL.Query(), productMarketQuery)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+	params.Filters = append(params.Filters, query.Filter{Field: "provider_id", Op: query.Eq, Value: providerID})
+
+	productMarkets, err := h.usecase.List(r.Context(), params)
 	if err != nil {
-		httpx.SendInternalServerError(w, err.Error(), err)
+		httpx.SendError(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(productMarkets)
+	httpx.SendSuccess(w, productMarkets)
 }
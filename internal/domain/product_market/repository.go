@@ -1,24 +1,64 @@
 package product_market
 
 import (
+	"context"
 	"database/sql"
 	"market/pkg/database"
+	"market/pkg/query"
+	"market/pkg/security"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
-	FindByID(id uuid.UUID) (*ProductMarket, error)
-	FindByProviderID(providerID string) ([]*ProductMarket, error)
-	Save(productMarket *ProductMarket) (*ProductMarket, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*ProductMarket, error)
+	// List returns a page of prices, with one extra row, and the total matching the filters
+	List(ctx context.Context, params *query.Params) ([]*ProductMarket, int, error)
+	FindByMarketAndProviderID(ctx context.Context, marketID uuid.UUID, providerID string) (*ProductMarket, error)
+	FindActiveByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*ProductMarket, error)
+	Save(ctx context.Context, productMarket *ProductMarket) (*ProductMarket, error)
+	Update(ctx context.Context, productMarket *ProductMarket) (*ProductMarket, error)
 }
 
 type productMarketRepository struct {
 	db                      *database.PostgresDB
 	log                     *zap.SugaredLogger
 	createProductMarketStmt *sql.Stmt
-	findByProviderIDStmt    *sql.Stmt
+}
+
+// productMarketQuery is what clients may filter and sort price listings by
+var productMarketQuery = &query.Schema{
+	Fields: map[string]query.Field{
+		"id":          {Column: "pm.id", Type: query.UUID},
+		"provider_id": {Column: "pm.provider_id", Type: query.String, Ops: []query.Op{query.Eq}},
+		"product_id":  {Column: "pm.product_id", Type: query.UUID, Ops: []query.Op{query.Eq}},
+		"market_id":   {Column: "pm.market_id", Type: query.UUID, Ops: []query.Op{query.Eq}},
+		"store_id":    {Column: "pm.store_id", Type: query.UUID, Ops: []query.Op{query.Eq}},
+		"status":      {Column: "pm.status", Type: query.String, Ops: []query.Op{query.Eq}, Values: []string{"active", "inactive"}},
+		"price":       {Column: "pm.price", Type: query.Number, Ops: []query.Op{query.Eq, query.Lt, query.Lte, query.Gt, query.Gte}, Sortable: true},
+		"created_at":  {Column: "pm.created_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+		"updated_at":  {Column: "pm.updated_at", Type: query.Time, Ops: []query.Op{query.Gte, query.Lte}, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: []query.Sort{{Field: "price"}},
+	DefaultSize: 50,
+	MaxSize:     200,
+}
+
+// productMarketSortValue feeds the next cursor of price listings
+func productMarketSortValue(productMarket *ProductMarket, field string) any {
+	switch field {
+	case "price":
+		return productMarket.Price
+	case "created_at":
+		return productMarket.CreatedAt
+	case "updated_at":
+		return productMarket.UpdatedAt
+	default:
+		return productMarket.ID
+	}
 }
 
 func NewRepository(log *zap.SugaredLogger) Repository {
@@ -26,37 +66,28 @@ func NewRepository(log *zap.SugaredLogger) Repository {
 
 	// ProductMarket statements
 	insertProductMarket := `INSERT INTO product_markets 
-		(id, provider_id, product_id, market_id, price, promotional_price, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		(id, provider_id, product_id, market_id, store_id, company_id, price, promotional_price, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING created_at, updated_at`
 
-	findByProviderIDQuery := `SELECT id, provider_id, product_id, market_id, price, promotional_price, status, created_at, updated_at
-							 FROM product_markets WHERE provider_id = $1 AND status != 'deleted'`
-
 	// Prepare statements
 	createProductMarketStmt, err := dbInstance.Prepare(insertProductMarket)
 	if err != nil {
 		log.Errorw("error preparing create product market statement", "error", err)
 	}
 
-	findByProviderIDStmt, err := dbInstance.Prepare(findByProviderIDQuery)
-	if err != nil {
-		log.Errorw("error preparing find by provider ID statement", "error", err)
-	}
-
 	return &productMarketRepository{
 		db:                      dbInstance,
 		log:                     log,
 		createProductMarketStmt: createProductMarketStmt,
-		findByProviderIDStmt:    findByProviderIDStmt,
 	}
 }
 
-func (p *productMarketRepository) FindByID(id uuid.UUID) (*ProductMarket, error) {
-	sql := `SELECT id, provider_id, product_id, market_id, price, promotional_price, status, created_at, updated_at
-			FROM product_markets WHERE id = $1 AND status != 'deleted' LIMIT 1`
+func (p *productMarketRepository) FindByID(ctx context.Context, id uuid.UUID) (*ProductMarket, error) {
+	sql := `SELECT id, provider_id, product_id, market_id, store_id, company_id, price, promotional_price, status, created_at, updated_at
+			FROM product_markets WHERE id = $1 AND status != 'deleted' AND ` + companyFilter("company_id", "$2") + ` LIMIT 1`
 
-	rows, err := p.db.Query(sql, id)
+	rows, err := p.db.QueryContext(ctx, sql, id, security.CompanyID(ctx))
 	if err != nil {
 		p.log.Errorw("error executing FindByID", "error", err, "id", id)
 		return nil, err
@@ -70,6 +101,8 @@ func (p *productMarketRepository) FindByID(id uuid.UUID) (*ProductMarket, error)
 			&productMarket.ProviderID,
 			&productMarket.ProductID,
 			&productMarket.MarketID,
+			&productMarket.StoreID,
+			&productMarket.CompanyID,
 			&productMarket.Price,
 			&productMarket.PromotionalPrice,
 			&productMarket.Status,
@@ -88,15 +121,120 @@ func (p *productMarketRepository) FindByID(id uuid.UUID) (*ProductMarket, error)
 	return nil, nil
 }
 
-func (p *productMarketRepository) FindByProviderID(providerID string) ([]*ProductMarket, error) {
-	rows, err := p.findByProviderIDStmt.Query(providerID)
+func (p *productMarketRepository) List(ctx context.Context, params *query.Params) ([]*ProductMarket, int, error) {
+	b := params.NewBuilder(security.CompanyID(ctx))
+	where := `WHERE pm.status != 'deleted' AND ` + companyFilter("pm.company_id", "$1") + b.Filters()
+
+	var total int
+	countSQL := `SELECT COUNT(*) FROM product_markets pm ` + where
+	if err := p.db.QueryRowContext(ctx, countSQL, b.Args()...).Scan(&total); err != nil {
+		p.log.Errorw("error counting product markets", "error", err)
+		return nil, 0, err
+	}
+
+	listSQL := `SELECT pm.id, pm.provider_id, pm.product_id, pm.market_id, pm.store_id, pm.company_id, pm.price, pm.promotional_price, pm.status, pm.created_at, pm.updated_at
+			FROM product_markets pm ` + where + b.Page()
+
+	rows, err := p.db.QueryContext(ctx, listSQL, b.Args()...)
+	if err != nil {
+		p.log.Errorw("error executing List", "error", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	productMarkets := []*ProductMarket{}
+	for rows.Next() {
+		var productMarket ProductMarket
+		err = rows.Scan(
+			&productMarket.ID,
+			&productMarket.ProviderID,
+			&productMarket.ProductID,
+			&productMarket.MarketID,
+			&productMarket.StoreID,
+			&productMarket.CompanyID,
+			&productMarket.Price,
+			&productMarket.PromotionalPrice,
+			&productMarket.Status,
+			&productMarket.CreatedAt,
+			&productMarket.UpdatedAt,
+		)
+
+		if err != nil {
+			p.log.Errorw("error scanning product market list", "error", err)
+			return nil, 0, err
+		}
+
+		productMarkets = append(productMarkets, &productMarket)
+	}
+
+	if err = rows.Err(); err != nil {
+		p.log.Errorw("error iterating product market list", "error", err)
+		return nil, 0, err
+	}
+
+	return productMarkets, total, nil
+}
+
+func (p *productMarketRepository) FindByMarketAndProviderID(ctx context.Context, marketID uuid.UUID, providerID string) (*ProductMarket, error) {
+	sql := `SELECT id, provider_id, product_id, market_id, store_id, company_id, price, promotional_price, status, created_at, updated_at
+			FROM product_markets WHERE market_id = $1 AND provider_id = $2 AND status != 'deleted'
+			AND ` + companyFilter("company_id", "$3") + ` LIMIT 1`
+
+	rows, err := p.db.QueryContext(ctx, sql, marketID, providerID, security.CompanyID(ctx))
+	if err != nil {
+		p.log.Errorw("error executing FindByMarketAndProviderID", "error", err, "market_id", marketID, "provider_id", providerID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var productMarket ProductMarket
+	if rows.Next() {
+		err = rows.Scan(
+			&productMarket.ID,
+			&productMarket.ProviderID,
+			&productMarket.ProductID,
+			&productMarket.MarketID,
+			&productMarket.StoreID,
+			&productMarket.CompanyID,
+			&productMarket.Price,
+			&productMarket.PromotionalPrice,
+			&productMarket.Status,
+			&productMarket.CreatedAt,
+			&productMarket.UpdatedAt,
+		)
+
+		if err != nil {
+			p.log.Errorw("error scanning product market by market and provider ID", "error", err, "market_id", marketID, "provider_id", providerID)
+			return nil, err
+		}
+
+		return &productMarket, nil
+	}
+
+	return nil, nil
+}
+
+// FindActiveByProductIDs returns the active offers of active markets for the given products
+func (p *productMarketRepository) FindActiveByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*ProductMarket, error) {
+	sql := `SELECT pm.id, pm.provider_id, pm.product_id, pm.market_id, pm.store_id, pm.company_id, pm.price, pm.promotional_price, pm.status, pm.created_at, pm.updated_at
+			FROM product_markets pm
+			INNER JOIN markets m ON m.id = pm.market_id AND m.status = 'active'
+			WHERE pm.product_id = ANY($1::uuid[]) AND pm.status = 'active'
+				AND ` + companyFilter("pm.company_id", "$2")
+
+	ids := make([]string, 0, len(productIDs))
+	for _, id := range productIDs {
+		ids = append(ids, id.String())
+	}
+
+	rows, err := p.db.QueryContext(ctx, sql, pq.Array(ids), security.CompanyID(ctx))
 	if err != nil {
-		p.log.Errorw("error executing FindByProviderID", "error", err, "provider_id", providerID)
+		p.log.Errorw("error executing FindActiveByProductIDs", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var productMarkets []*ProductMarket
+	productMarkets := []*ProductMarket{}
 	for rows.Next() {
 		var productMarket ProductMarket
 		err = rows.Scan(
@@ -104,6 +242,8 @@ func (p *productMarketRepository) FindByProviderID(providerID string) ([]*Produc
 			&productMarket.ProviderID,
 			&productMarket.ProductID,
 			&productMarket.MarketID,
+			&productMarket.StoreID,
+			&productMarket.CompanyID,
 			&productMarket.Price,
 			&productMarket.PromotionalPrice,
 			&productMarket.Status,
@@ -112,7 +252,7 @@ func (p *productMarketRepository) FindByProviderID(providerID string) ([]*Produc
 		)
 
 		if err != nil {
-			p.log.Errorw("error scanning product market by provider ID", "error", err, "provider_id", providerID)
+			p.log.Errorw("error scanning product markets by product IDs", "error", err)
 			return nil, err
 		}
 
@@ -120,19 +260,22 @@ func (p *productMarketRepository) FindByProviderID(providerID string) ([]*Produc
 	}
 
 	if err = rows.Err(); err != nil {
-		p.log.Errorw("error iterating product markets by provider ID", "error", err, "provider_id", providerID)
+		p.log.Errorw("error iterating product markets by product IDs", "error", err)
 		return nil, err
 	}
 
 	return productMarkets, nil
 }
 
-func (p *productMarketRepository) Save(productMarket *ProductMarket) (*ProductMarket, error) {
-	err := p.createProductMarketStmt.QueryRow(
+func (p *productMarketRepository) Save(ctx context.Context, productMarket *ProductMarket) (*ProductMarket, error) {
+	err := p.db.Stmt(ctx, p.createProductMarketStmt).QueryRowContext(
+		ctx,
 		productMarket.ID,
 		productMarket.ProviderID,
 		productMarket.ProductID,
 		productMarket.MarketID,
+		productMarket.StoreID,
+		productMarket.CompanyID,
 		productMarket.Price,
 		productMarket.PromotionalPrice,
 		productMarket.Status,
@@ -145,3 +288,33 @@ func (p *productMarketRepository) Save(productMarket *ProductMarket) (*ProductMa
 
 	return productMarket, nil
 }
+
+func (p *productMarketRepository) Update(ctx context.Context, productMarket *ProductMarket) (*ProductMarket, error) {
+	sql := `UPDATE product_markets SET
+		price = $2, promotional_price = $3, status = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ` + companyFilter("company_id", "$5") + `
+		RETURNING created_at, updated_at`
+
+	err := p.db.QueryRowContext(
+		ctx,
+		sql,
+		productMarket.ID,
+		productMarket.Price,
+		productMarket.PromotionalPrice,
+		productMarket.Status,
+		security.CompanyID(ctx),
+	).Scan(&productMarket.CreatedAt, &productMarket.UpdatedAt)
+
+	if err != nil {
+		p.log.Errorw("error updating product market", "error", err, "id", productMarket.ID)
+		return nil, err
+	}
+
+	return productMarket, nil
+}
+
+// companyFilter keeps the shared prices and the private prices of the caller
+// company, param is the placeholder holding the company ID
+func companyFilter(column, param string) string {
+	return `(` + column + ` IS NULL OR ` + column + ` = ` + param + `)`
+}
@@ -1,44 +1,60 @@
 package product_market
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"market/internal/domain/market"
+	"market/internal/domain/price_history"
+	"market/pkg/apperr"
+	"market/pkg/query"
+	"market/pkg/security"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrStoreNotInMarket = apperr.Validation("store_not_in_market", "store not found for this market")
+)
+
 type UseCase interface {
-	CreateProductMarket(dto *ProductMarketCreateDTO) (*ProductMarketResponseDTO, error)
-	FindByProviderID(providerID string) ([]*ProductMarket, error)
+	CreateProductMarket(ctx context.Context, dto *ProductMarketCreateDTO) (*ProductMarketResponseDTO, error)
+	List(ctx context.Context, params *query.Params) (*query.Page[*ProductMarket], error)
 }
 
 type service struct {
-	log           *zap.SugaredLogger
-	repository    Repository
-	marketService market.UseCase
+	log                    *zap.SugaredLogger
+	repository             Repository
+	marketService          market.UseCase
+	priceHistoryRepository price_history.Repository
 }
 
 func NewService(
 	log *zap.SugaredLogger,
 ) UseCase {
 	return &service{
-		log:           log,
-		repository:    NewRepository(log),
-		marketService: market.NewService(log),
+		log:                    log,
+		repository:             NewRepository(log),
+		marketService:          market.NewService(log),
+		priceHistoryRepository: price_history.NewRepository(log),
 	}
 }
 
 // ProductMarket methods
-func (s *service) CreateProductMarket(dto *ProductMarketCreateDTO) (*ProductMarketResponseDTO, error) {
-	// Basic validation
-	if dto.Price <= 0 {
-		return nil, fmt.Errorf("price must be greater than 0")
+func (s *service) CreateProductMarket(ctx context.Context, dto *ProductMarketCreateDTO) (*ProductMarketResponseDTO, error) {
+	// A branch price must point to a store of the same market
+	if dto.StoreID != nil {
+		if _, err := s.marketService.FindStore(ctx, dto.MarketID, *dto.StoreID); err != nil {
+			if errors.Is(err, market.ErrStoreNotFound) {
+				return nil, ErrStoreNotInMarket
+			}
+			return nil, err
+		}
 	}
 
-	if dto.PromotionalPrice != nil && *dto.PromotionalPrice <= 0 {
-		return nil, fmt.Errorf("promotional price must be greater than 0")
-	}
+	// Prices informed through the API are private to the caller company
+	companyID := security.CompanyID(ctx)
 
 	// Create product market entity
 	productMarket := &ProductMarket{
@@ -46,24 +62,42 @@ func (s *service) CreateProductMarket(dto *ProductMarketCreateDTO) (*ProductMark
 		ProviderID:       dto.ProviderID,
 		ProductID:        dto.ProductID,
 		MarketID:         dto.MarketID,
+		StoreID:          dto.StoreID,
+		CompanyID:        &companyID,
 		Price:            dto.Price,
 		PromotionalPrice: dto.PromotionalPrice,
 		Status:           ProductMarketStatusActive,
 	}
 
 	// Save to repository
-	savedProductMarket, err := s.repository.Save(productMarket)
+	savedProductMarket, err := s.repository.Save(ctx, productMarket)
 	if err != nil {
 		s.log.Errorw("error saving product market", "error", err)
 		return nil, fmt.Errorf("error saving product market: %w", err)
 	}
 
+	// A failed history entry should not discard the price itself
+	history := price_history.NewPriceHistory(
+		savedProductMarket.ProductID,
+		savedProductMarket.MarketID,
+		savedProductMarket.Price,
+		savedProductMarket.PromotionalPrice,
+		price_history.SourceManual,
+	)
+	history.CompanyID = savedProductMarket.CompanyID
+	err = s.priceHistoryRepository.Save(ctx, history)
+	if err != nil {
+		s.log.Errorw("error recording price history", "error", err, "product_market_id", savedProductMarket.ID)
+	}
+
 	// Convert to response DTO
 	responseDTO := &ProductMarketResponseDTO{
 		ID:               savedProductMarket.ID,
 		ProviderID:       savedProductMarket.ProviderID,
 		ProductID:        savedProductMarket.ProductID,
 		MarketID:         savedProductMarket.MarketID,
+		StoreID:          savedProductMarket.StoreID,
+		CompanyID:        savedProductMarket.CompanyID,
 		Price:            savedProductMarket.Price,
 		PromotionalPrice: savedProductMarket.PromotionalPrice,
 		Status:           savedProductMarket.Status,
@@ -74,16 +108,12 @@ func (s *service) CreateProductMarket(dto *ProductMarketCreateDTO) (*ProductMark
 	return responseDTO, nil
 }
 
-func (s *service) FindByProviderID(providerID string) ([]*ProductMarket, error) {
-	if providerID == "" {
-		return nil, fmt.Errorf("provider ID is required")
-	}
-
-	productMarkets, err := s.repository.FindByProviderID(providerID)
+func (s *service) List(ctx context.Context, params *query.Params) (*query.Page[*ProductMarket], error) {
+	productMarkets, total, err := s.repository.List(ctx, params)
 	if err != nil {
-		s.log.Errorw("error finding product markets by provider ID", "error", err, "provider_id", providerID)
-		return nil, fmt.Errorf("error finding product markets: %w", err)
+		s.log.Errorw("error listing product markets", "error", err)
+		return nil, fmt.Errorf("error listing product markets: %w", err)
 	}
 
-	return productMarkets, nil
+	return query.NewPage(productMarkets, total, params, productMarketSortValue), nil
 }
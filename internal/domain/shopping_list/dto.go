@@ -0,0 +1,35 @@
+package shopping_list
+
+import (
+	"github.com/google/uuid"
+)
+
+// ShoppingList DTOs
+type ListCreateDTO struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type ListUpdateDTO struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// Item DTOs
+type ItemCreateDTO struct {
+	ProductID   *uuid.UUID `json:"product_id,omitempty"`
+	Description *string    `json:"description,omitempty" validate:"omitempty,max=255"`
+	Quantity    float64    `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+	Unit        *string    `json:"unit,omitempty" validate:"omitempty,max=20"`
+}
+
+type ItemUpdateDTO struct {
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	Quantity    *float64 `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+	Unit        *string  `json:"unit,omitempty" validate:"omitempty,max=20"`
+	Checked     *bool    `json:"checked,omitempty"`
+}
+
+// Member DTOs
+type MemberCreateDTO struct {
+	Email      string     `json:"email" validate:"required,email"`
+	Permission Permission `json:"permission" validate:"required,oneof=viewer editor"`
+}
@@ -0,0 +1,90 @@
+package shopping_list
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListStatus string
+
+const (
+	ListStatusActive  ListStatus = "active"
+	ListStatusDeleted ListStatus = "deleted"
+)
+
+// Permission is the access level of a user on a list. The owner is not stored
+// in shopping_list_members, it is derived from shopping_lists.owner_id.
+type Permission string
+
+const (
+	PermissionOwner  Permission = "owner"
+	PermissionEditor Permission = "editor"
+	PermissionViewer Permission = "viewer"
+)
+
+// ShoppingList representa uma lista de compras de um usuário
+type ShoppingList struct {
+	ID           uuid.UUID  `json:"id"`
+	OwnerID      uuid.UUID  `json:"owner_id"`
+	CompanyID    uuid.UUID  `json:"company_id"`
+	Name         string     `json:"name"`
+	Status       ListStatus `json:"status"`
+	Permission   Permission `json:"permission"`
+	ItemsCount   int        `json:"items_count"`
+	CheckedCount int        `json:"checked_count"`
+	Items        []*Item    `json:"items,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Item is either a catalog product or a free text entry
+type Item struct {
+	ID          uuid.UUID  `json:"id"`
+	ListID      uuid.UUID  `json:"list_id"`
+	ProductID   *uuid.UUID `json:"product_id,omitempty"`
+	ProductName *string    `json:"product_name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Quantity    float64    `json:"quantity"`
+	Unit        *string    `json:"unit,omitempty"`
+	Checked     bool       `json:"checked"`
+	CheckedBy   *uuid.UUID `json:"checked_by,omitempty"`
+	CheckedAt   *time.Time `json:"checked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Member is a user the list was shared with
+type Member struct {
+	ListID     uuid.UUID  `json:"list_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Permission Permission `json:"permission"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CanEdit reports whether the permission allows changing the list and its items
+func (p Permission) CanEdit() bool {
+	return p == PermissionOwner || p == PermissionEditor
+}
+
+// IsShareable checks if the permission can be granted to a member
+func (p Permission) IsShareable() bool {
+	return p == PermissionEditor || p == PermissionViewer
+}
+
+// Check marks the item as bought by the given user
+func (i *Item) Check(userID uuid.UUID) {
+	now := time.Now()
+	i.Checked = true
+	i.CheckedBy = &userID
+	i.CheckedAt = &now
+}
+
+// Uncheck clears the check-off state
+func (i *Item) Uncheck() {
+	i.Checked = false
+	i.CheckedBy = nil
+	i.CheckedAt = nil
+}
@@ -0,0 +1,434 @@
+package shopping_list
+
+import (
+	"market/pkg/httpx"
+	"market/pkg/security"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase UseCase
+}
+
+func NewHandler(uc UseCase) *Handler {
+	return &Handler{
+		usecase: uc,
+	}
+}
+
+// CreateListHandler godoc
+// @Summary      Criar lista de compras
+// @Tags         shopping-lists
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		ListCreateDTO	true	"List data"
+// @Success      201		{object}	ShoppingList
+// @Failure      400		{object}	httpx.Problem
+// @Router       /shopping-lists [post]
+func (h *Handler) CreateListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var dto ListCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	list, err := h.usecase.Create(r.Context(), userCtx.UserID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, list)
+}
+
+// ListListsHandler godoc
+// @Summary      Listar listas de compras
+// @Description  Lista as listas do usuário e as compartilhadas com ele
+// @Tags         shopping-lists
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200	{array}		ShoppingList
+// @Router       /shopping-lists [get]
+func (h *Handler) ListListsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.usecase.List(r.Context(), userCtx.UserID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, lists)
+}
+
+// GetListHandler godoc
+// @Summary      Obter lista de compras
+// @Description  Retorna a lista com seus itens
+// @Tags         shopping-lists
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"List ID"
+// @Success      200	{object}	ShoppingList
+// @Failure      404	{object}	httpx.Problem
+// @Router       /shopping-lists/{id} [get]
+func (h *Handler) GetListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.usecase.Get(r.Context(), userCtx.UserID, listID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, list)
+}
+
+// UpdateListHandler godoc
+// @Summary      Renomear lista de compras
+// @Tags         shopping-lists
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"List ID"
+// @Param        request	body		ListUpdateDTO	true	"List data"
+// @Success      200		{object}	ShoppingList
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id} [put]
+func (h *Handler) UpdateListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var dto ListUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	list, err := h.usecase.Update(r.Context(), userCtx.UserID, listID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, list)
+}
+
+// DeleteListHandler godoc
+// @Summary      Remover lista de compras
+// @Description  Somente o dono pode remover a lista
+// @Tags         shopping-lists
+// @Security     ApiKeyAuth
+// @Param        id		path	string	true	"List ID"
+// @Success      204	"No Content"
+// @Failure      403	{object}	httpx.Problem
+// @Failure      404	{object}	httpx.Problem
+// @Router       /shopping-lists/{id} [delete]
+func (h *Handler) DeleteListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), userCtx.UserID, listID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddItemHandler godoc
+// @Summary      Adicionar item à lista
+// @Description  Adiciona um produto do catálogo ou um item em texto livre
+// @Tags         shopping-lists
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"List ID"
+// @Param        request	body		ItemCreateDTO	true	"Item data"
+// @Success      201		{object}	Item
+// @Failure      400		{object}	httpx.Problem
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/items [post]
+func (h *Handler) AddItemHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var dto ItemCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	item, err := h.usecase.AddItem(r.Context(), userCtx.UserID, listID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, item)
+}
+
+// UpdateItemHandler godoc
+// @Summary      Atualizar item da lista
+// @Description  Altera quantidade, unidade, descrição ou marca/desmarca o item
+// @Tags         shopping-lists
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"List ID"
+// @Param        item_id	path		string			true	"Item ID"
+// @Param        request	body		ItemUpdateDTO	true	"Item data"
+// @Success      200		{object}	Item
+// @Failure      400		{object}	httpx.Problem
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/items/{item_id} [put]
+func (h *Handler) UpdateItemHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	itemID, ok := parseID(w, r, "item_id")
+	if !ok {
+		return
+	}
+
+	var dto ItemUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	item, err := h.usecase.UpdateItem(r.Context(), userCtx.UserID, listID, itemID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, item)
+}
+
+// RemoveItemHandler godoc
+// @Summary      Remover item da lista
+// @Tags         shopping-lists
+// @Security     ApiKeyAuth
+// @Param        id			path	string	true	"List ID"
+// @Param        item_id	path	string	true	"Item ID"
+// @Success      204		"No Content"
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/items/{item_id} [delete]
+func (h *Handler) RemoveItemHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	itemID, ok := parseID(w, r, "item_id")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RemoveItem(r.Context(), userCtx.UserID, listID, itemID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMembersHandler godoc
+// @Summary      Listar membros da lista
+// @Tags         shopping-lists
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id		path		string	true	"List ID"
+// @Success      200	{array}		Member
+// @Failure      404	{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/members [get]
+func (h *Handler) ListMembersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	members, err := h.usecase.ListMembers(r.Context(), userCtx.UserID, listID)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, members)
+}
+
+// ShareListHandler godoc
+// @Summary      Compartilhar lista
+// @Description  Compartilha a lista com outro usuário pelo email, como viewer ou editor
+// @Tags         shopping-lists
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"List ID"
+// @Param        request	body		MemberCreateDTO	true	"Member data"
+// @Success      201		{object}	Member
+// @Failure      400		{object}	httpx.Problem
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/members [post]
+func (h *Handler) ShareListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var dto MemberCreateDTO
+	if err := httpx.DecodeJSON(w, r, &dto); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	member, err := h.usecase.Share(r.Context(), userCtx.UserID, listID, &dto)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendCreated(w, member)
+}
+
+// UnshareListHandler godoc
+// @Summary      Remover membro da lista
+// @Description  O dono pode remover qualquer membro; um membro pode sair da lista
+// @Tags         shopping-lists
+// @Security     ApiKeyAuth
+// @Param        id			path	string	true	"List ID"
+// @Param        user_id	path	string	true	"Member user ID"
+// @Success      204		"No Content"
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /shopping-lists/{id}/members/{user_id} [delete]
+func (h *Handler) UnshareListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userCtx, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	memberID, ok := parseID(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Unshare(r.Context(), userCtx.UserID, listID, memberID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request) (*security.UserAuth, bool) {
+	userCtx, err := security.GetUser(r.Context())
+	if err != nil {
+		httpx.SendUnauthorized(w, "Authentication required")
+		return nil, false
+	}
+	return userCtx, true
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		httpx.SendBadRequest(w, "Invalid "+name+" format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,422 @@
+package shopping_list
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"market/pkg/security"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Create(ctx context.Context, list *ShoppingList) error
+	FindAccessible(ctx context.Context, id, userID uuid.UUID) (*ShoppingList, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*ShoppingList, error)
+	Update(ctx context.Context, list *ShoppingList) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	ListItems(ctx context.Context, listID uuid.UUID) ([]*Item, error)
+	FindItem(ctx context.Context, listID, itemID uuid.UUID) (*Item, error)
+	SaveItem(ctx context.Context, item *Item) error
+	UpdateItem(ctx context.Context, item *Item) error
+	DeleteItem(ctx context.Context, listID, itemID uuid.UUID) error
+
+	ListMembers(ctx context.Context, listID uuid.UUID) ([]*Member, error)
+	SaveMember(ctx context.Context, member *Member) error
+	DeleteMember(ctx context.Context, listID, userID uuid.UUID) error
+}
+
+type repository struct {
+	db                  *database.PostgresDB
+	log                 *zap.SugaredLogger
+	createListStatement *sql.Stmt
+	createItemStatement *sql.Stmt
+}
+
+// listColumns resolves the caller permission, userParam is the placeholder holding the user ID
+func listColumns(userParam string) string {
+	return `l.id, l.owner_id, l.company_id, l.name, l.status, l.created_at, l.updated_at,
+	CASE WHEN l.owner_id = ` + userParam + ` THEN 'owner' ELSE m.permission END,
+	(SELECT COUNT(*) FROM shopping_list_items i WHERE i.list_id = l.id),
+	(SELECT COUNT(*) FROM shopping_list_items i WHERE i.list_id = l.id AND i.checked)`
+}
+
+const itemColumns = `i.id, i.list_id, i.product_id, p.name, i.description, i.quantity, i.unit,
+	i.checked, i.checked_by, i.checked_at, i.created_at, i.updated_at`
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insertList := `INSERT INTO shopping_lists
+		(id, owner_id, company_id, name, status, created_at, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	RETURNING created_at, updated_at`
+
+	insertItem := `INSERT INTO shopping_list_items
+		(id, list_id, product_id, description, quantity, unit, checked, created_at, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	RETURNING created_at, updated_at`
+
+	createListStatement, err := dbInstance.Prepare(insertList)
+	if err != nil {
+		log.Errorw("error on create shopping list statement", "error", err)
+	}
+
+	createItemStatement, err := dbInstance.Prepare(insertItem)
+	if err != nil {
+		log.Errorw("error on create shopping list item statement", "error", err)
+	}
+
+	return &repository{
+		db:                  dbInstance,
+		log:                 log,
+		createListStatement: createListStatement,
+		createItemStatement: createItemStatement,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, list *ShoppingList) error {
+	err := r.db.Stmt(ctx, r.createListStatement).QueryRowContext(
+		ctx,
+		list.ID,
+		list.OwnerID,
+		list.CompanyID,
+		list.Name,
+		list.Status,
+	).Scan(&list.CreatedAt, &list.UpdatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute Create", "error", err, "owner_id", list.OwnerID)
+		return err
+	}
+	return nil
+}
+
+// FindAccessible returns the list only when the user owns it or is a member,
+// and the list belongs to the caller company
+func (r *repository) FindAccessible(ctx context.Context, id, userID uuid.UUID) (*ShoppingList, error) {
+	sql := `SELECT ` + listColumns("$2") + `
+	FROM shopping_lists l
+	LEFT JOIN shopping_list_members m ON m.list_id = l.id AND m.user_id = $2
+	WHERE l.id = $1 AND l.status != 'deleted' AND (l.owner_id = $2 OR m.user_id IS NOT NULL)
+		AND l.company_id = $3
+	LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, id, userID, security.CompanyID(ctx))
+	if err != nil {
+		r.log.Errorw("error on execute FindAccessible", "error", err, "id", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			r.log.Errorw("error on scan FindAccessible", "error", err, "id", id)
+			return nil, err
+		}
+		return list, nil
+	}
+
+	return nil, nil
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*ShoppingList, error) {
+	sql := `SELECT ` + listColumns("$1") + `
+	FROM shopping_lists l
+	LEFT JOIN shopping_list_members m ON m.list_id = l.id AND m.user_id = $1
+	WHERE l.status != 'deleted' AND (l.owner_id = $1 OR m.user_id IS NOT NULL)
+		AND l.company_id = $2
+	ORDER BY l.updated_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, sql, userID, security.CompanyID(ctx))
+	if err != nil {
+		r.log.Errorw("error on execute ListByUser", "error", err, "user_id", userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []*ShoppingList{}
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			r.log.Errorw("error on scan ListByUser", "error", err, "user_id", userID)
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error on iterate ListByUser", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (r *repository) Update(ctx context.Context, list *ShoppingList) error {
+	sql := `UPDATE shopping_lists SET name = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, sql, list.ID, list.Name).Scan(&list.UpdatedAt)
+	if err != nil {
+		r.log.Errorw("error on execute Update", "error", err, "id", list.ID)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE shopping_lists SET status = 'deleted', updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, sql, id); err != nil {
+		r.log.Errorw("error on execute Delete", "error", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+// Item methods
+func (r *repository) ListItems(ctx context.Context, listID uuid.UUID) ([]*Item, error) {
+	sql := `SELECT ` + itemColumns + `
+	FROM shopping_list_items i
+	LEFT JOIN products p ON p.id = i.product_id
+	WHERE i.list_id = $1
+	ORDER BY i.checked, i.created_at`
+
+	rows, err := r.db.QueryContext(ctx, sql, listID)
+	if err != nil {
+		r.log.Errorw("error on execute ListItems", "error", err, "list_id", listID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			r.log.Errorw("error on scan ListItems", "error", err, "list_id", listID)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error on iterate ListItems", "error", err, "list_id", listID)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *repository) FindItem(ctx context.Context, listID, itemID uuid.UUID) (*Item, error) {
+	sql := `SELECT ` + itemColumns + `
+	FROM shopping_list_items i
+	LEFT JOIN products p ON p.id = i.product_id
+	WHERE i.list_id = $1 AND i.id = $2
+	LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, listID, itemID)
+	if err != nil {
+		r.log.Errorw("error on execute FindItem", "error", err, "id", itemID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			r.log.Errorw("error on scan FindItem", "error", err, "id", itemID)
+			return nil, err
+		}
+		return item, nil
+	}
+
+	return nil, nil
+}
+
+func (r *repository) SaveItem(ctx context.Context, item *Item) error {
+	err := r.db.Stmt(ctx, r.createItemStatement).QueryRowContext(
+		ctx,
+		item.ID,
+		item.ListID,
+		item.ProductID,
+		item.Description,
+		item.Quantity,
+		item.Unit,
+		item.Checked,
+	).Scan(&item.CreatedAt, &item.UpdatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute SaveItem", "error", err, "list_id", item.ListID)
+		return err
+	}
+
+	r.touch(ctx, item.ListID)
+	return nil
+}
+
+func (r *repository) UpdateItem(ctx context.Context, item *Item) error {
+	sql := `UPDATE shopping_list_items SET
+		description = $2, quantity = $3, unit = $4, checked = $5, checked_by = $6, checked_at = $7,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		sql,
+		item.ID,
+		item.Description,
+		item.Quantity,
+		item.Unit,
+		item.Checked,
+		item.CheckedBy,
+		item.CheckedAt,
+	).Scan(&item.UpdatedAt)
+
+	if err != nil {
+		r.log.Errorw("error on execute UpdateItem", "error", err, "id", item.ID)
+		return err
+	}
+
+	r.touch(ctx, item.ListID)
+	return nil
+}
+
+func (r *repository) DeleteItem(ctx context.Context, listID, itemID uuid.UUID) error {
+	sql := `DELETE FROM shopping_list_items WHERE list_id = $1 AND id = $2`
+
+	if _, err := r.db.ExecContext(ctx, sql, listID, itemID); err != nil {
+		r.log.Errorw("error on execute DeleteItem", "error", err, "id", itemID)
+		return err
+	}
+
+	r.touch(ctx, listID)
+	return nil
+}
+
+// Member methods
+func (r *repository) ListMembers(ctx context.Context, listID uuid.UUID) ([]*Member, error) {
+	sql := `SELECT m.list_id, m.user_id, u.name, u.email, m.permission, m.created_at
+	FROM shopping_list_members m
+	INNER JOIN users u ON u.id = m.user_id
+	WHERE m.list_id = $1
+	ORDER BY u.name`
+
+	rows, err := r.db.QueryContext(ctx, sql, listID)
+	if err != nil {
+		r.log.Errorw("error on execute ListMembers", "error", err, "list_id", listID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*Member{}
+	for rows.Next() {
+		var member Member
+		err = rows.Scan(
+			&member.ListID,
+			&member.UserID,
+			&member.Name,
+			&member.Email,
+			&member.Permission,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			r.log.Errorw("error on scan ListMembers", "error", err, "list_id", listID)
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error on iterate ListMembers", "error", err, "list_id", listID)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// SaveMember shares the list or changes the permission of an existing member
+func (r *repository) SaveMember(ctx context.Context, member *Member) error {
+	sql := `INSERT INTO shopping_list_members (list_id, user_id, permission, created_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+	ON CONFLICT (list_id, user_id) DO UPDATE SET permission = EXCLUDED.permission
+	RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, sql, member.ListID, member.UserID, member.Permission).Scan(&member.CreatedAt)
+	if err != nil {
+		r.log.Errorw("error on execute SaveMember", "error", err, "list_id", member.ListID, "user_id", member.UserID)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteMember(ctx context.Context, listID, userID uuid.UUID) error {
+	sql := `DELETE FROM shopping_list_members WHERE list_id = $1 AND user_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, sql, listID, userID); err != nil {
+		r.log.Errorw("error on execute DeleteMember", "error", err, "list_id", listID, "user_id", userID)
+		return err
+	}
+	return nil
+}
+
+// touch bumps the list updated_at so recently changed lists come first
+func (r *repository) touch(ctx context.Context, listID uuid.UUID) {
+	sql := `UPDATE shopping_lists SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, sql, listID); err != nil {
+		r.log.Warnw("error on touch shopping list", "error", err, "id", listID)
+	}
+}
+
+func scanList(rows *sql.Rows) (*ShoppingList, error) {
+	var list ShoppingList
+	err := rows.Scan(
+		&list.ID,
+		&list.OwnerID,
+		&list.CompanyID,
+		&list.Name,
+		&list.Status,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+		&list.Permission,
+		&list.ItemsCount,
+		&list.CheckedCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func scanItem(rows *sql.Rows) (*Item, error) {
+	var item Item
+	err := rows.Scan(
+		&item.ID,
+		&item.ListID,
+		&item.ProductID,
+		&item.ProductName,
+		&item.Description,
+		&item.Quantity,
+		&item.Unit,
+		&item.Checked,
+		&item.CheckedBy,
+		&item.CheckedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
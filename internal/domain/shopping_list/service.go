@@ -0,0 +1,371 @@
+package shopping_list
+
+import (
+	"context"
+	"fmt"
+	"market/internal/domain/company"
+	"market/internal/domain/product"
+	"market/internal/domain/user"
+	"market/pkg/apperr"
+	"market/pkg/security"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrListNotFound      = apperr.NotFound("shopping_list_not_found", "shopping list not found")
+	ErrItemNotFound      = apperr.NotFound("shopping_list_item_not_found", "shopping list item not found")
+	ErrProductNotFound   = apperr.Validation("product_not_found", "product not found")
+	ErrUserNotFound      = apperr.Validation("user_not_found", "user not found")
+	ErrForbidden         = apperr.Forbidden("shopping_list_forbidden", "you do not have permission to change this list")
+	ErrNameRequired      = apperr.Validation("name_required", "name is required")
+	ErrInvalidItem       = apperr.Validation("invalid_item", "item must have a product_id or a description")
+	ErrInvalidQuantity   = apperr.Validation("invalid_quantity", "quantity must be greater than 0")
+	ErrInvalidPermission = apperr.Validation("invalid_permission", "permission must be viewer or editor")
+	ErrShareWithOwner    = apperr.Validation("share_with_owner", "the owner already has access to the list")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, userID uuid.UUID, dto *ListCreateDTO) (*ShoppingList, error)
+	Get(ctx context.Context, userID, id uuid.UUID) (*ShoppingList, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*ShoppingList, error)
+	Update(ctx context.Context, userID, id uuid.UUID, dto *ListUpdateDTO) (*ShoppingList, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	AddItem(ctx context.Context, userID, listID uuid.UUID, dto *ItemCreateDTO) (*Item, error)
+	UpdateItem(ctx context.Context, userID, listID, itemID uuid.UUID, dto *ItemUpdateDTO) (*Item, error)
+	RemoveItem(ctx context.Context, userID, listID, itemID uuid.UUID) error
+
+	ListMembers(ctx context.Context, userID, listID uuid.UUID) ([]*Member, error)
+	Share(ctx context.Context, userID, listID uuid.UUID, dto *MemberCreateDTO) (*Member, error)
+	Unshare(ctx context.Context, userID, listID, memberID uuid.UUID) error
+}
+
+type service struct {
+	log               *zap.SugaredLogger
+	repository        Repository
+	productRepository product.Repository
+	userRepository    user.Repository
+	companyRepository company.Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:               log,
+		repository:        NewRepository(log),
+		productRepository: product.NewRepository(log),
+		userRepository:    user.NewRepository(log),
+		companyRepository: company.NewRepository(log),
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, dto *ListCreateDTO) (*ShoppingList, error) {
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	list := &ShoppingList{
+		ID:         uuid.New(),
+		OwnerID:    userID,
+		CompanyID:  security.CompanyID(ctx),
+		Name:       name,
+		Status:     ListStatusActive,
+		Permission: PermissionOwner,
+		Items:      []*Item{},
+	}
+
+	if err := s.repository.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("error creating shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+func (s *service) Get(ctx context.Context, userID, id uuid.UUID) (*ShoppingList, error) {
+	list, err := s.access(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Items, err = s.repository.ListItems(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shopping list items: %w", err)
+	}
+
+	return list, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]*ShoppingList, error) {
+	lists, err := s.repository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shopping lists: %w", err)
+	}
+	return lists, nil
+}
+
+func (s *service) Update(ctx context.Context, userID, id uuid.UUID, dto *ListUpdateDTO) (*ShoppingList, error) {
+	list, err := s.editable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	list.Name = name
+
+	if err := s.repository.Update(ctx, list); err != nil {
+		return nil, fmt.Errorf("error updating shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+// Delete is only allowed to the owner, members can leave through Unshare
+func (s *service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	list, err := s.access(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if list.Permission != PermissionOwner {
+		return ErrForbidden
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error deleting shopping list: %w", err)
+	}
+	return nil
+}
+
+// Item methods
+func (s *service) AddItem(ctx context.Context, userID, listID uuid.UUID, dto *ItemCreateDTO) (*Item, error) {
+	if _, err := s.editable(ctx, userID, listID); err != nil {
+		return nil, err
+	}
+
+	if dto.Description != nil {
+		description := strings.TrimSpace(*dto.Description)
+		dto.Description = &description
+		if description == "" {
+			dto.Description = nil
+		}
+	}
+	if dto.ProductID == nil && dto.Description == nil {
+		return nil, ErrInvalidItem
+	}
+
+	if dto.Quantity == 0 {
+		dto.Quantity = 1
+	}
+	if dto.Quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	item := &Item{
+		ID:          uuid.New(),
+		ListID:      listID,
+		ProductID:   dto.ProductID,
+		Description: dto.Description,
+		Quantity:    dto.Quantity,
+		Unit:        dto.Unit,
+	}
+
+	if dto.ProductID != nil {
+		p, err := s.productRepository.FindByID(ctx, *dto.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("error finding product: %w", err)
+		}
+		if p == nil || p.Status == product.ProductStatusDeleted {
+			return nil, ErrProductNotFound
+		}
+		item.ProductName = &p.Name
+		if item.Unit == nil {
+			item.Unit = p.Unit
+		}
+	}
+
+	if err := s.repository.SaveItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("error adding shopping list item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (s *service) UpdateItem(ctx context.Context, userID, listID, itemID uuid.UUID, dto *ItemUpdateDTO) (*Item, error) {
+	if _, err := s.editable(ctx, userID, listID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.findItem(ctx, listID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update only provided fields
+	if dto.Description != nil {
+		description := strings.TrimSpace(*dto.Description)
+		if description == "" && item.ProductID == nil {
+			return nil, ErrInvalidItem
+		}
+		item.Description = &description
+		if description == "" {
+			item.Description = nil
+		}
+	}
+	if dto.Quantity != nil {
+		if *dto.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		item.Quantity = *dto.Quantity
+	}
+	if dto.Unit != nil {
+		item.Unit = dto.Unit
+	}
+	if dto.Checked != nil && *dto.Checked != item.Checked {
+		if *dto.Checked {
+			item.Check(userID)
+		} else {
+			item.Uncheck()
+		}
+	}
+
+	if err := s.repository.UpdateItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("error updating shopping list item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (s *service) RemoveItem(ctx context.Context, userID, listID, itemID uuid.UUID) error {
+	if _, err := s.editable(ctx, userID, listID); err != nil {
+		return err
+	}
+
+	if _, err := s.findItem(ctx, listID, itemID); err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteItem(ctx, listID, itemID); err != nil {
+		return fmt.Errorf("error removing shopping list item: %w", err)
+	}
+	return nil
+}
+
+// Member methods
+func (s *service) ListMembers(ctx context.Context, userID, listID uuid.UUID) ([]*Member, error) {
+	if _, err := s.access(ctx, userID, listID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repository.ListMembers(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shopping list members: %w", err)
+	}
+	return members, nil
+}
+
+// Share grants access to another user of the list company by email, sharing
+// again changes the permission
+func (s *service) Share(ctx context.Context, userID, listID uuid.UUID, dto *MemberCreateDTO) (*Member, error) {
+	list, err := s.access(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.Permission != PermissionOwner {
+		return nil, ErrForbidden
+	}
+
+	if !dto.Permission.IsShareable() {
+		return nil, ErrInvalidPermission
+	}
+
+	u, err := s.userRepository.FindByEmail(ctx, strings.TrimSpace(dto.Email))
+	if err != nil {
+		return nil, fmt.Errorf("error finding user: %w", err)
+	}
+	if u == nil || u.Status == user.UserStatusDeleted {
+		return nil, ErrUserNotFound
+	}
+	if u.ID == list.OwnerID {
+		return nil, ErrShareWithOwner
+	}
+
+	// Users of other companies are reported as not found
+	member, err := s.companyRepository.IsMember(ctx, list.CompanyID, u.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking company member: %w", err)
+	}
+	if !member {
+		return nil, ErrUserNotFound
+	}
+
+	listMember := &Member{
+		ListID:     listID,
+		UserID:     u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		Permission: dto.Permission,
+	}
+
+	if err := s.repository.SaveMember(ctx, listMember); err != nil {
+		return nil, fmt.Errorf("error sharing shopping list: %w", err)
+	}
+
+	return listMember, nil
+}
+
+// Unshare removes a member; the owner can remove anyone and members can remove themselves
+func (s *service) Unshare(ctx context.Context, userID, listID, memberID uuid.UUID) error {
+	list, err := s.access(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if list.Permission != PermissionOwner && userID != memberID {
+		return ErrForbidden
+	}
+
+	if err := s.repository.DeleteMember(ctx, listID, memberID); err != nil {
+		return fmt.Errorf("error removing shopping list member: %w", err)
+	}
+	return nil
+}
+
+// access loads the list for the user, lists the user cannot see are reported as not found
+func (s *service) access(ctx context.Context, userID, listID uuid.UUID) (*ShoppingList, error) {
+	list, err := s.repository.FindAccessible(ctx, listID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding shopping list: %w", err)
+	}
+	if list == nil {
+		return nil, ErrListNotFound
+	}
+	return list, nil
+}
+
+func (s *service) editable(ctx context.Context, userID, listID uuid.UUID) (*ShoppingList, error) {
+	list, err := s.access(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Permission.CanEdit() {
+		return nil, ErrForbidden
+	}
+	return list, nil
+}
+
+func (s *service) findItem(ctx context.Context, listID, itemID uuid.UUID) (*Item, error) {
+	item, err := s.repository.FindItem(ctx, listID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding shopping list item: %w", err)
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+	return item, nil
+}
@@ -0,0 +1,7 @@
+package sync_run
+
+type SyncRunFilterDTO struct {
+	Provider string
+	Status   SyncRunStatus
+	Limit    int
+}
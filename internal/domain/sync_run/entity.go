@@ -0,0 +1,63 @@
+package sync_run
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SyncRunStatus string
+
+const (
+	SyncRunStatusRunning SyncRunStatus = "running"
+	SyncRunStatusSuccess SyncRunStatus = "success"
+	SyncRunStatusFailed  SyncRunStatus = "failed"
+)
+
+type SyncRunTrigger string
+
+const (
+	SyncRunTriggerSchedule SyncRunTrigger = "schedule"
+	SyncRunTriggerManual   SyncRunTrigger = "manual"
+	SyncRunTriggerCLI      SyncRunTrigger = "cli"
+)
+
+// SyncRun representa uma execução de sincronização de um provider
+type SyncRun struct {
+	ID               uuid.UUID      `json:"id"`
+	Provider         string         `json:"provider"`
+	TriggeredBy      SyncRunTrigger `json:"triggered_by"`
+	Status           SyncRunStatus  `json:"status"`
+	PagesFetched     int            `json:"pages_fetched"`
+	ProductsFetched  int            `json:"products_fetched"`
+	ProductsUpserted int            `json:"products_upserted"`
+	Errors           int            `json:"errors"`
+	// UnmappedCategories are the crawled categories without internal category
+	UnmappedCategories []string   `json:"unmapped_categories"`
+	ErrorMessage       *string    `json:"error_message,omitempty"`
+	StartedAt          time.Time  `json:"started_at"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty"`
+}
+
+func NewSyncRun(provider string, trigger SyncRunTrigger) *SyncRun {
+	return &SyncRun{
+		ID:          uuid.New(),
+		Provider:    provider,
+		TriggeredBy: trigger,
+		Status:      SyncRunStatusRunning,
+		StartedAt:   time.Now(),
+	}
+}
+
+// Finish sets the run as finished, failed when err is not nil
+func (r *SyncRun) Finish(err error) {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.Status = SyncRunStatusSuccess
+
+	if err != nil {
+		message := err.Error()
+		r.Status = SyncRunStatusFailed
+		r.ErrorMessage = &message
+	}
+}
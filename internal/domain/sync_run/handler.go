@@ -0,0 +1,85 @@
+package sync_run
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+	"strconv"
+)
+
+// Trigger starts a provider sync in background
+type Trigger interface {
+	Trigger(provider string) (*SyncRun, error)
+}
+
+type Handler struct {
+	usecase UseCase
+	trigger Trigger
+}
+
+func NewHandler(uc UseCase, trigger Trigger) *Handler {
+	return &Handler{
+		usecase: uc,
+		trigger: trigger,
+	}
+}
+
+// ListSyncRunsHandler godoc
+// @Summary      Listar execuções de sincronização
+// @Description  Retorna as últimas execuções de sincronização dos providers
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        provider	query		string	false	"Provider name"
+// @Param        status		query		string	false	"running, success or failed"
+// @Param        limit		query		int		false	"Max runs returned"	default(50)
+// @Success      200		{array}		SyncRun
+// @Failure      400		{object}	httpx.Problem
+// @Router       /admin/sync-runs [get]
+func (h *Handler) ListSyncRunsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter := &SyncRunFilterDTO{
+		Provider: r.URL.Query().Get("provider"),
+		Status:   SyncRunStatus(r.URL.Query().Get("status")),
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			httpx.SendBadRequest(w, "Invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	runs, err := h.usecase.List(r.Context(), filter)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, runs)
+}
+
+// TriggerSyncHandler godoc
+// @Summary      Disparar sincronização de provider
+// @Description  Inicia em background a sincronização do provider informado
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        name	path		string	true	"Provider name"
+// @Success      202	{object}	SyncRun
+// @Failure      404	{object}	httpx.Problem
+// @Failure      409	{object}	httpx.Problem
+// @Router       /admin/providers/{name}/sync [post]
+func (h *Handler) TriggerSyncHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	run, err := h.trigger.Trigger(r.PathValue("name"))
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendAccepted(w, run)
+}
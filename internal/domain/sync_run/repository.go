@@ -0,0 +1,174 @@
+package sync_run
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Create(ctx context.Context, run *SyncRun) error
+	Update(ctx context.Context, run *SyncRun) error
+	// FailRunning marks the provider runs still running as failed with message
+	FailRunning(ctx context.Context, provider, message string) error
+	List(ctx context.Context, filter *SyncRunFilterDTO) ([]*SyncRun, error)
+	LastSuccessAt(ctx context.Context) (*time.Time, error)
+}
+
+type repository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+	updateStatement *sql.Stmt
+}
+
+func NewRepository(
+	log *zap.SugaredLogger,
+) Repository {
+
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO sync_runs
+		(id, provider, triggered_by, status, started_at)
+	VALUES
+		($1, $2, $3, $4, $5);`
+
+	update := `UPDATE sync_runs SET
+		status = $2, pages_fetched = $3, products_fetched = $4, products_upserted = $5,
+		errors = $6, unmapped_categories = $7, error_message = $8, finished_at = $9
+	WHERE id = $1;`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	updateStatement, err := dbInstance.Prepare(update)
+	if err != nil {
+		log.Errorw("error on update statement", "error", err)
+	}
+
+	return &repository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+		updateStatement: updateStatement,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, run *SyncRun) error {
+	_, err := r.db.Stmt(ctx, r.createStatement).ExecContext(
+		ctx,
+		run.ID,
+		run.Provider,
+		run.TriggeredBy,
+		run.Status,
+		run.StartedAt,
+	)
+
+	if err != nil {
+		r.log.Errorw("error on execute Create", "error", err, "provider", run.Provider)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) Update(ctx context.Context, run *SyncRun) error {
+	_, err := r.db.Stmt(ctx, r.updateStatement).ExecContext(
+		ctx,
+		run.ID,
+		run.Status,
+		run.PagesFetched,
+		run.ProductsFetched,
+		run.ProductsUpserted,
+		run.Errors,
+		pq.Array(run.UnmappedCategories),
+		run.ErrorMessage,
+		run.FinishedAt,
+	)
+
+	if err != nil {
+		r.log.Errorw("error on execute Update", "error", err, "id", run.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) FailRunning(ctx context.Context, provider, message string) error {
+	sql := `UPDATE sync_runs SET status = $3, error_message = $4, finished_at = CURRENT_TIMESTAMP
+		WHERE provider = $1 AND status = $2`
+
+	_, err := r.db.ExecContext(ctx, sql, provider, SyncRunStatusRunning, SyncRunStatusFailed, message)
+	if err != nil {
+		r.log.Errorw("error on execute FailRunning", "error", err, "provider", provider)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) List(ctx context.Context, filter *SyncRunFilterDTO) ([]*SyncRun, error) {
+	sql := `SELECT id, provider, triggered_by, status, pages_fetched, products_fetched, products_upserted,
+			errors, unmapped_categories, error_message, started_at, finished_at
+		FROM sync_runs
+		WHERE ($1 = '' OR provider = $1) AND ($2 = '' OR status = $2)
+		ORDER BY started_at DESC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, sql, filter.Provider, string(filter.Status), filter.Limit)
+	if err != nil {
+		r.log.Errorw("error on execute List", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*SyncRun{}
+	for rows.Next() {
+		var run SyncRun
+		err = rows.Scan(
+			&run.ID,
+			&run.Provider,
+			&run.TriggeredBy,
+			&run.Status,
+			&run.PagesFetched,
+			&run.ProductsFetched,
+			&run.ProductsUpserted,
+			&run.Errors,
+			pq.Array(&run.UnmappedCategories),
+			&run.ErrorMessage,
+			&run.StartedAt,
+			&run.FinishedAt,
+		)
+		if err != nil {
+			r.log.Errorw("error on scan List", "error", err)
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error iterating List", "error", err)
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// LastSuccessAt returns when the last successful run of any provider finished
+func (r *repository) LastSuccessAt(ctx context.Context) (*time.Time, error) {
+	sql := `SELECT MAX(finished_at) FROM sync_runs WHERE status = $1`
+
+	var finishedAt *time.Time
+	if err := r.db.QueryRowContext(ctx, sql, SyncRunStatusSuccess).Scan(&finishedAt); err != nil {
+		r.log.Errorw("error on execute LastSuccessAt", "error", err)
+		return nil, err
+	}
+
+	return finishedAt, nil
+}
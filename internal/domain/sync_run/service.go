@@ -0,0 +1,100 @@
+package sync_run
+
+import (
+	"context"
+	"fmt"
+	"market/pkg/apperr"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// interruptedMessage is recorded on runs left running by a process that died
+const interruptedMessage = "interrupted before finishing"
+
+var (
+	ErrProviderNotFound = apperr.NotFound("provider_not_found", "provider not found")
+	ErrAlreadyRunning   = apperr.Conflict("sync_already_running", "provider sync already running")
+	ErrSchedulerStopped = apperr.Conflict("sync_scheduler_stopped", "sync scheduler is shutting down")
+)
+
+type UseCase interface {
+	Start(ctx context.Context, provider string, trigger SyncRunTrigger) (*SyncRun, error)
+	Finish(ctx context.Context, run *SyncRun) error
+	List(ctx context.Context, filter *SyncRunFilterDTO) ([]*SyncRun, error)
+	LastSuccessAt(ctx context.Context) (*time.Time, error)
+}
+
+type service struct {
+	log        *zap.SugaredLogger
+	repository Repository
+}
+
+func NewService(
+	log *zap.SugaredLogger,
+) UseCase {
+	return &service{
+		log:        log,
+		repository: NewRepository(log),
+	}
+}
+
+// Start records a new run. Callers hold the provider lock, so runs of the
+// provider still marked as running were interrupted and are marked as failed.
+func (s *service) Start(ctx context.Context, provider string, trigger SyncRunTrigger) (*SyncRun, error) {
+	if err := s.repository.FailRunning(ctx, provider, interruptedMessage); err != nil {
+		s.log.Errorw("error failing interrupted sync runs", "error", err, "provider", provider)
+		return nil, fmt.Errorf("error failing interrupted sync runs: %w", err)
+	}
+
+	run := NewSyncRun(provider, trigger)
+
+	if err := s.repository.Create(ctx, run); err != nil {
+		s.log.Errorw("error creating sync run", "error", err, "provider", provider)
+		return nil, fmt.Errorf("error creating sync run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (s *service) Finish(ctx context.Context, run *SyncRun) error {
+	if err := s.repository.Update(ctx, run); err != nil {
+		s.log.Errorw("error finishing sync run", "error", err, "id", run.ID)
+		return fmt.Errorf("error finishing sync run: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) List(ctx context.Context, filter *SyncRunFilterDTO) ([]*SyncRun, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	runs, err := s.repository.List(ctx, filter)
+	if err != nil {
+		s.log.Errorw("error listing sync runs", "error", err)
+		return nil, fmt.Errorf("error listing sync runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// LastSuccessAt returns nil when no provider was ever synced successfully
+func (s *service) LastSuccessAt(ctx context.Context) (*time.Time, error) {
+	finishedAt, err := s.repository.LastSuccessAt(ctx)
+	if err != nil {
+		s.log.Errorw("error finding last successful sync run", "error", err)
+		return nil, fmt.Errorf("error finding last successful sync run: %w", err)
+	}
+
+	return finishedAt, nil
+}
@@ -1,54 +1,55 @@
 package user
 
 import (
-	"errors"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type UserCreateDTO struct {
-	Email    string `json:"email"`
-	Name     string `json:"name"`
-	Password string `json:"password"`
-	market   string `json:"market"`
+	Email    string `json:"email" validate:"required,email,max=80"`
+	Name     string `json:"name" validate:"required,max=80"`
+	Password string `json:"password" validate:"required,maxbytes=72"`
 }
 
 type UserLoginDTO struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,max=80"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ClientInfo identifies where a login request came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type CompanySwitchDTO struct {
+	CompanyID uuid.UUID `json:"company_id" validate:"required"`
+}
+
+type PasswordForgotDTO struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type PasswordResetDTO struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,maxbytes=72"`
+}
+
+type RoleUpdateDTO struct {
+	Role Role `json:"role" validate:"required,oneof=admin curator member"`
 }
 
 type UserFoundDTO struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-// Validate verifica se os campos obrigatórios do UserCreateDTO estão corretamente preenchidos.
-func (dto *UserCreateDTO) Validate() error {
-	if strings.TrimSpace(dto.Email) == "" {
-		return errors.New("email não pode ser vazio")
-	}
-	if strings.TrimSpace(dto.Name) == "" {
-		return errors.New("nome não pode ser vazio")
-	}
-	if strings.TrimSpace(dto.Password) == "" {
-		return errors.New("senha não pode ser vazia")
-	}
-	return nil
-}
-
-// Validate verifica se os campos obrigatórios do UserLoginDTO estão corretamente preenchidos.
-func (dto *UserLoginDTO) Validate() error {
-	if strings.TrimSpace(dto.Email) == "" {
-		return errors.New("email não pode ser vazio")
-	}
-	if strings.TrimSpace(dto.Password) == "" {
-		return errors.New("senha não pode ser vazia")
-	}
-	return nil
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Role          Role      `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"errors"
+	"market/pkg/apperr"
+	"market/pkg/validate"
+	"strings"
+	"testing"
+)
+
+// bcrypt refuses passwords over 72 bytes, fewer characters when multibyte
+func TestPasswordFitsBcrypt(t *testing.T) {
+	input := &UserCreateDTO{Email: "ana@market.app", Name: "Ana", Password: strings.Repeat("ç", 40)}
+	if err := validate.Struct(input); !errors.Is(err, apperr.ErrValidation) {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+
+	reset := &PasswordResetDTO{Token: "token", Password: strings.Repeat("ç", 36)}
+	if err := validate.Struct(reset); err != nil {
+		t.Fatalf("72 byte password rejected: %v", err)
+	}
+}
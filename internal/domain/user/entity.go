@@ -15,6 +15,25 @@ const (
 	UserStatusDeleted  UserStatus = "deleted"
 )
 
+// Role defines what a user can do: members use the app, curators maintain
+// the catalog and admins also run the providers sync and manage users
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleCurator Role = "curator"
+	RoleMember  Role = "member"
+)
+
+// IsValid checks if the role is one of the known roles
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleCurator, RoleMember:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID            uuid.UUID  `json:"id"`
 	Name          string     `json:"name"`
@@ -22,38 +41,103 @@ type User struct {
 	Password      string     `json:"password"`
 	EmailVerified bool       `json:"email_verified"`
 	LastLogin     *time.Time `json:"last_login,omitempty"`
+	FailedLogins  int        `json:"-"`
+	LockedUntil   *time.Time `json:"-"`
 	Status        UserStatus `json:"status"`
+	Role          Role       `json:"role"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
+
+// Locked tells whether the account must still wait before a new login attempt
+func (u *User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
 type UserToken struct {
-	Email       string `json:"email"`
-	Name        string `json:"name"`
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresAt   int64  `json:"expires_at"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresAt    int64  `json:"expires_at"`
 }
 
 type Token struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresAt   int64  `json:"expires_at"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+// RefreshToken is the server side record of an opaque refresh token. Only the
+// hash is stored, so a leaked table does not allow renewing sessions.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	CompanyID  *uuid.UUID `json:"company_id,omitempty"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Active tells whether the token can still be exchanged
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
 }
 
 func (t *UserToken) NewUserToken(user *User, token *Token) *UserToken {
 	t.Email = user.Email
 	t.Name = user.Name
 	t.AccessToken = token.AccessToken
+	t.RefreshToken = token.RefreshToken
 	t.TokenType = token.TokenType
 	t.ExpiresAt = token.ExpiresAt
 
 	return t
 }
 
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+)
+
+// UserTokenRecord is a single use token sent by email. Only its hash is stored.
+type UserTokenRecord struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Purpose   TokenPurpose `json:"purpose"`
+	TokenHash string       `json:"-"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Usable tells whether the token was not used and did not expire
+func (t *UserTokenRecord) Usable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// LoginAttempt records a sign in, successful or not. UserID is nil when the
+// email does not belong to any account.
+type LoginAttempt struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    *uuid.UUID `json:"-"`
+	Email     string     `json:"-"`
+	IP        string     `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+	Success   bool       `json:"success"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
 // Estrutura para as claims do JWT
 type Claims struct {
 	Email     string    `json:"email"`
-	Role      string    `json:"role"`
+	Role      Role      `json:"role"`
 	UserID    uuid.UUID `json:"user_id"`
 	CompanyID uuid.UUID `json:"company_id"`
 	jwt.StandardClaims
@@ -1,10 +1,16 @@
 package user
 
 import (
+	"encoding/json"
+	"errors"
+	"market/pkg/config"
 	"market/pkg/httpx"
 	"market/pkg/security"
-	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
 )
 
 type Handler struct {
@@ -25,22 +31,22 @@ func NewHandler(uc UseCase) *Handler {
 // @Produce      json
 // @Param        request	body		UserCreateDTO	true	"Dados do usuário"
 // @Success      201		{object}	UserFoundDTO
-// @Failure      400		{object}	map[string]string
+// @Failure      400		{object}	httpx.Problem
 // @Router       /auth/register [post]
 func (h *Handler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var registerDTO *UserCreateDTO
-	err := json.NewDecoder(r.Body).Decode(&registerDTO)
+	err := httpx.DecodeJSON(w, r, &registerDTO)
 
 	if err != nil {
-		httpx.SendBadRequest(w, "Invalid request body")
+		httpx.SendError(w, err)
 		return
 	}
 
-	userAuth, err := h.usecase.Register(registerDTO)
+	userAuth, err := h.usecase.Register(r.Context(), registerDTO)
 	if err != nil {
-		httpx.SendBadRequest(w, "Failed to register user")
+		httpx.SendError(w, err)
 		return
 	}
 
@@ -55,28 +61,249 @@ func (h *Handler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        request	body		UserLoginDTO	true	"Dados de login"
 // @Success      200		{object}	UserFoundDTO
-// @Failure      400		{object}	map[string]string
+// @Failure      400		{object}	httpx.Problem
+// @Failure      429		{object}	httpx.Problem
 // @Router       /auth/login [post]
 func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var loginDTO *UserLoginDTO
-	err := json.NewDecoder(r.Body).Decode(&loginDTO)
+	err := httpx.DecodeJSON(w, r, &loginDTO)
 
 	if err != nil {
-		httpx.SendBadRequest(w, "Invalid request body")
+		httpx.SendError(w, err)
 		return
 	}
 
-	userAuth, err := h.usecase.Login(loginDTO)
+	client := ClientInfo{
+		IP:        httpx.ClientIP(r, trustedProxyHops()),
+		UserAgent: r.UserAgent(),
+	}
+
+	userAuth, err := h.usecase.Login(r.Context(), loginDTO, client)
 	if err != nil {
-		httpx.SendBadRequest(w, "Failed to login user")
+		var tooMany *TooManyAttemptsError
+		if errors.As(err, &tooMany) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(tooMany.RetryAfter.Seconds()))))
+			httpx.SendTooManyRequests(w, ErrTooManyAttempts.Error())
+			return
+		}
+		httpx.SendError(w, err)
 		return
 	}
 
 	json.NewEncoder(w).Encode(userAuth)
 }
 
+// RefreshHandler godoc
+// @Summary      Renovar tokens
+// @Description  Troca um refresh token válido por um novo par de tokens, revogando o usado
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request	body		RefreshTokenDTO	true	"Refresh token"
+// @Success      200		{object}	UserToken
+// @Failure      400		{object}	httpx.Problem
+// @Failure      401		{object}	httpx.Problem
+// @Router       /auth/refresh [post]
+func (h *Handler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var refreshDTO *RefreshTokenDTO
+	if err := httpx.DecodeJSON(w, r, &refreshDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	userAuth, err := h.usecase.Refresh(r.Context(), refreshDTO)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, userAuth)
+}
+
+// LogoutHandler godoc
+// @Summary      Encerrar sessão
+// @Description  Revoga o refresh token informado
+// @Tags         auth
+// @Accept       json
+// @Param        request	body		RefreshTokenDTO	true	"Refresh token"
+// @Success      204
+// @Failure      400		{object}	httpx.Problem
+// @Router       /auth/logout [post]
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	var logoutDTO *RefreshTokenDTO
+	if err := httpx.DecodeJSON(w, r, &logoutDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	if err := h.usecase.Logout(r.Context(), logoutDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SwitchCompanyHandler godoc
+// @Summary      Trocar empresa ativa
+// @Description  Emite novos tokens para outra empresa do usuário autenticado
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request	body		CompanySwitchDTO	true	"Empresa"
+// @Success      200		{object}	UserToken
+// @Failure      403		{object}	httpx.Problem
+// @Router       /auth/switch-company [post]
+func (h *Handler) SwitchCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := security.GetUser(r.Context())
+	if err != nil {
+		httpx.SendUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	var switchDTO *CompanySwitchDTO
+	if err := httpx.DecodeJSON(w, r, &switchDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	userAuth, err := h.usecase.SwitchCompany(r.Context(), userCtx.UserID, switchDTO)
+	if err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, userAuth)
+}
+
+// RequestEmailVerificationHandler godoc
+// @Summary      Reenviar verificação de e-mail
+// @Description  Envia um novo link de verificação para o e-mail do usuário autenticado
+// @Tags         auth
+// @Security     ApiKeyAuth
+// @Success      202
+// @Failure      409		{object}	httpx.Problem
+// @Router       /auth/verify-email/request [post]
+func (h *Handler) RequestEmailVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := security.GetUser(r.Context())
+	if err != nil {
+		httpx.SendUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	if err := h.usecase.RequestEmailVerification(r.Context(), userCtx.UserID); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// VerifyEmailHandler godoc
+// @Summary      Verificar e-mail
+// @Description  Confirma o e-mail usando o token enviado por e-mail
+// @Tags         auth
+// @Produce      json
+// @Param        token	query		string	true	"Token de verificação"
+// @Success      200		{object}	map[string]string
+// @Failure      400		{object}	httpx.Problem
+// @Router       /auth/verify-email [get]
+func (h *Handler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.usecase.VerifyEmail(r.Context(), r.URL.Query().Get("token")); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	httpx.SendSuccess(w, map[string]string{"message": "E-mail verificado"})
+}
+
+// ForgotPasswordHandler godoc
+// @Summary      Esqueci minha senha
+// @Description  Envia um link de redefinição de senha quando o e-mail está cadastrado
+// @Tags         auth
+// @Accept       json
+// @Param        request	body		PasswordForgotDTO	true	"E-mail"
+// @Success      202
+// @Failure      400		{object}	httpx.Problem
+// @Router       /auth/password/forgot [post]
+func (h *Handler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var forgotDTO *PasswordForgotDTO
+	if err := httpx.DecodeJSON(w, r, &forgotDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	if err := h.usecase.ForgotPassword(r.Context(), forgotDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPasswordHandler godoc
+// @Summary      Redefinir senha
+// @Description  Define uma nova senha usando o token enviado por e-mail e encerra as sessões abertas
+// @Tags         auth
+// @Accept       json
+// @Param        request	body		PasswordResetDTO	true	"Token e nova senha"
+// @Success      204
+// @Failure      400		{object}	httpx.Problem
+// @Router       /auth/password/reset [post]
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var resetDTO *PasswordResetDTO
+	if err := httpx.DecodeJSON(w, r, &resetDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	if err := h.usecase.ResetPassword(r.Context(), resetDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangeRoleHandler godoc
+// @Summary      Alterar papel do usuário
+// @Description  Define o papel (admin, curator ou member) de um usuário
+// @Tags         admin
+// @Accept       json
+// @Security     ApiKeyAuth
+// @Param        id			path		string			true	"User ID"
+// @Param        request	body		RoleUpdateDTO	true	"Novo papel"
+// @Success      204
+// @Failure      400		{object}	httpx.Problem
+// @Failure      403		{object}	httpx.Problem
+// @Failure      404		{object}	httpx.Problem
+// @Router       /admin/users/{id}/role [put]
+func (h *Handler) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpx.SendBadRequest(w, "invalid user id")
+		return
+	}
+
+	var roleDTO *RoleUpdateDTO
+	if err := httpx.DecodeJSON(w, r, &roleDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	if err := h.usecase.ChangeRole(r.Context(), id, roleDTO); err != nil {
+		httpx.SendError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // MeHandler godoc
 // @Summary      Obter dados do usuário autenticado
 // @Description  Retorna os dados do usuário atualmente autenticado
@@ -85,21 +312,56 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Security     ApiKeyAuth
 // @Success      200		{object}	UserFoundDTO
-// @Failure      401		{object}	map[string]string
+// @Failure      401		{object}	httpx.Problem
 // @Router       /auth/me [get]
 func (h *Handler) MeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	userCtx, _ := security.GetUser(r.Context())
 
-	userFound, err := h.usecase.Me(userCtx.UserID)
+	userFound, err := h.usecase.Me(r.Context(), userCtx.UserID)
 	if err != nil {
-		httpx.SendBadRequest(w, "Failed to retrieve user")
+		httpx.SendError(w, err)
 		return
 	}
 
 	if err := json.NewEncoder(w).Encode(userFound); err != nil {
-		httpx.SendInternalServerError(w, "Failed to encode user data", err)
+		httpx.SendInternalServerError(w, "Failed to encode user data")
+		return
+	}
+}
+
+// SessionsHandler godoc
+// @Summary      Listar acessos recentes
+// @Description  Retorna os últimos logins da conta, com IP, navegador e resultado
+// @Tags         auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200		{array}		LoginAttempt
+// @Failure      401		{object}	httpx.Problem
+// @Router       /auth/me/sessions [get]
+func (h *Handler) SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := security.GetUser(r.Context())
+	if err != nil {
+		httpx.SendUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.usecase.Sessions(r.Context(), userCtx.UserID)
+	if err != nil {
+		httpx.SendError(w, err)
 		return
 	}
+
+	httpx.SendSuccess(w, sessions)
+}
+
+// trustedProxyHops is how many X-Forwarded-For entries the proxies in front of
+// the API add, none when the proxy headers are not trusted
+func trustedProxyHops() int {
+	cfg := config.Get()
+	if !cfg.TRUST_PROXY_HEADERS {
+		return 0
+	}
+	return cfg.TRUSTED_PROXY_HOPS
 }
@@ -0,0 +1,72 @@
+package user
+
+import (
+	"fmt"
+	"market/pkg/config"
+	"time"
+)
+
+// TooManyAttemptsError is returned while an account or IP must wait before
+// trying to sign in again
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *TooManyAttemptsError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// LoginPolicy decides how long an account waits after failed logins: the
+// first FreeAttempts are free, then the wait doubles from BaseDelay on every
+// failure until LockAfter failures lock the account for LockDuration
+type LoginPolicy struct {
+	FreeAttempts  int
+	BaseDelay     time.Duration
+	LockAfter     int
+	LockDuration  time.Duration
+	IPMaxFailures int
+	IPWindow      time.Duration
+}
+
+func LoginPolicyFromConfig() LoginPolicy {
+	env := config.Get()
+	return LoginPolicy{
+		FreeAttempts:  env.LOGIN_FREE_ATTEMPTS,
+		BaseDelay:     env.LOGIN_BASE_DELAY,
+		LockAfter:     env.LOGIN_LOCK_AFTER,
+		LockDuration:  env.LOGIN_LOCK_DURATION,
+		IPMaxFailures: env.LOGIN_IP_MAX_FAILURES,
+		IPWindow:      env.LOGIN_IP_WINDOW,
+	}
+}
+
+// Delay returns the wait imposed after the given number of consecutive failures
+func (p LoginPolicy) Delay(failures int) time.Duration {
+	if p.LockAfter > 0 && failures >= p.LockAfter {
+		return p.LockDuration
+	}
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && delay < p.LockDuration; i++ {
+		delay *= 2
+	}
+	return min(delay, p.LockDuration)
+}
+
+// LockedUntil returns when the account can try again, nil when it is not locked
+func (p LoginPolicy) LockedUntil(failures int, now time.Time) *time.Time {
+	delay := p.Delay(failures)
+	if delay <= 0 {
+		return nil
+	}
+
+	until := now.Add(delay)
+	return &until
+}
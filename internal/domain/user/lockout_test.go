@@ -0,0 +1,55 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoginPolicyDelay(t *testing.T) {
+	policy := LoginPolicy{FreeAttempts: 3, BaseDelay: 5 * time.Second, LockAfter: 10, LockDuration: 30 * time.Minute}
+
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, 5 * time.Second},
+		{5, 10 * time.Second},
+		{7, 40 * time.Second},
+		{9, 160 * time.Second},
+		{10, 30 * time.Minute},
+		{25, 30 * time.Minute},
+	}
+
+	for _, c := range cases {
+		if got := policy.Delay(c.failures); got != c.want {
+			t.Errorf("Delay(%d) = %s, want %s", c.failures, got, c.want)
+		}
+	}
+}
+
+func TestLoginPolicyLockedUntil(t *testing.T) {
+	policy := LoginPolicy{FreeAttempts: 1, BaseDelay: time.Minute, LockAfter: 3, LockDuration: time.Hour}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	if until := policy.LockedUntil(1, now); until != nil {
+		t.Fatalf("free attempt locked until %s", until)
+	}
+	if until := policy.LockedUntil(3, now); until == nil || !until.Equal(now.Add(time.Hour)) {
+		t.Fatalf("LockedUntil(3) = %v, want %s", until, now.Add(time.Hour))
+	}
+}
+
+func TestTooManyAttemptsError(t *testing.T) {
+	var err error = &TooManyAttemptsError{RetryAfter: 90 * time.Second}
+
+	if !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatal("errors.Is(err, ErrTooManyAttempts) = false")
+	}
+	var tooMany *TooManyAttemptsError
+	if !errors.As(err, &tooMany) || tooMany.RetryAfter != 90*time.Second {
+		t.Fatalf("errors.As failed: %v", err)
+	}
+}
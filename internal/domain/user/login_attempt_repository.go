@@ -0,0 +1,139 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Sizes of the login_attempts columns
+const (
+	maxAttemptEmail     = 80
+	maxAttemptIP        = 45
+	maxAttemptUserAgent = 255
+)
+
+// truncate cuts s to n characters, the unit of VARCHAR(n)
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+type LoginAttemptRepository interface {
+	Save(ctx context.Context, attempt *LoginAttempt) error
+	// FailuresByIP counts failures from the IP since the given time, also returning the oldest one
+	FailuresByIP(ctx context.Context, ip string, since time.Time) (int, *time.Time, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+func NewLoginAttemptRepository(log *zap.SugaredLogger) LoginAttemptRepository {
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO login_attempts
+		(id, user_id, email, ip, user_agent, success, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7);`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &loginAttemptRepository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *loginAttemptRepository) Save(ctx context.Context, attempt *LoginAttempt) error {
+	_, err := r.db.Stmt(ctx, r.createStatement).ExecContext(
+		ctx,
+		attempt.ID,
+		attempt.UserID,
+		attempt.Email,
+		attempt.IP,
+		attempt.UserAgent,
+		attempt.Success,
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		r.log.Errorw("error on execute Save", "error", err, "email", attempt.Email)
+		return err
+	}
+
+	return nil
+}
+
+func (r *loginAttemptRepository) FailuresByIP(ctx context.Context, ip string, since time.Time) (int, *time.Time, error) {
+	sql := `SELECT COUNT(*), MIN(created_at) FROM login_attempts
+		WHERE ip = $1 AND NOT success AND created_at >= $2`
+
+	var (
+		count  int
+		oldest *time.Time
+	)
+	if err := r.db.QueryRowContext(ctx, sql, ip, since).Scan(&count, &oldest); err != nil {
+		r.log.Errorw("error on execute FailuresByIP", "error", err, "ip", ip)
+		return 0, nil, err
+	}
+
+	return count, oldest, nil
+}
+
+func (r *loginAttemptRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*LoginAttempt, error) {
+	sql := `SELECT id, user_id, email, ip, user_agent, success, created_at
+		FROM login_attempts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, sql, userID, limit)
+	if err != nil {
+		r.log.Errorw("error on execute ListByUser", "error", err, "user_id", userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*LoginAttempt{}
+	for rows.Next() {
+		var attempt LoginAttempt
+		err = rows.Scan(
+			&attempt.ID,
+			&attempt.UserID,
+			&attempt.Email,
+			&attempt.IP,
+			&attempt.UserAgent,
+			&attempt.Success,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			r.log.Errorw("error on scan ListByUser", "error", err)
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Errorw("error iterating ListByUser", "error", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// columnCheckingAttempts rejects values larger than the login_attempts
+// columns, like Postgres does
+type columnCheckingAttempts struct {
+	failures map[string]int
+}
+
+func (r *columnCheckingAttempts) Save(_ context.Context, attempt *LoginAttempt) error {
+	if utf8.RuneCountInString(attempt.Email) > maxAttemptEmail ||
+		utf8.RuneCountInString(attempt.IP) > maxAttemptIP ||
+		utf8.RuneCountInString(attempt.UserAgent) > maxAttemptUserAgent {
+		return fmt.Errorf("value too long for type character varying")
+	}
+	if !attempt.Success {
+		r.failures[attempt.IP]++
+	}
+	return nil
+}
+
+func (r *columnCheckingAttempts) FailuresByIP(_ context.Context, ip string, _ time.Time) (int, *time.Time, error) {
+	return r.failures[ip], nil, nil
+}
+
+func (r *columnCheckingAttempts) ListByUser(context.Context, uuid.UUID, int) ([]*LoginAttempt, error) {
+	return nil, nil
+}
+
+func TestRecordAttemptCountsOversizedClients(t *testing.T) {
+	attempts := &columnCheckingAttempts{failures: map[string]int{}}
+	s := &service{log: zap.NewNop().Sugar(), loginAttemptRepository: attempts}
+
+	client := ClientInfo{IP: "203.0.113.7", UserAgent: strings.Repeat("ü", 1000)}
+	s.recordAttempt(context.Background(), nil, strings.Repeat("a", 200)+"@market.app", client, false)
+
+	failures, _, _ := attempts.FailuresByIP(context.Background(), client.IP, time.Time{})
+	if failures != 1 {
+		t.Fatalf("failures = %d, want 1", failures)
+	}
+}
@@ -0,0 +1,122 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, token *RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	Revoke(ctx context.Context, id uuid.UUID, replacedBy *uuid.UUID) error
+	RevokeAllByUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type refreshTokenRepository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+func NewRefreshTokenRepository(log *zap.SugaredLogger) RefreshTokenRepository {
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO refresh_tokens
+		(id, user_id, company_id, token_hash, expires_at, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP);`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &refreshTokenRepository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *refreshTokenRepository) Save(ctx context.Context, token *RefreshToken) error {
+	_, err := r.db.Stmt(ctx, r.createStatement).ExecContext(
+		ctx,
+		token.ID,
+		token.UserID,
+		token.CompanyID,
+		token.TokenHash,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		r.log.Errorw("error on execute Save", "error", err, "user_id", token.UserID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, hash string) (*RefreshToken, error) {
+	sql := `SELECT id, user_id, company_id, token_hash, expires_at, revoked_at, replaced_by, created_at
+	FROM refresh_tokens WHERE token_hash = $1 LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, hash)
+	if err != nil {
+		r.log.Errorw("error on execute FindByHash", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	var token RefreshToken
+	err = rows.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.CompanyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		r.log.Errorw("error on scan FindByHash", "error", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Revoke keeps tokens already revoked untouched, so the first revocation time is preserved
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID, replacedBy *uuid.UUID) error {
+	sql := `UPDATE refresh_tokens SET revoked_at = $2, replaced_by = $3
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, sql, id, time.Now(), replacedBy)
+	if err != nil {
+		r.log.Errorw("error on execute Revoke", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeAllByUser(ctx context.Context, userID uuid.UUID) error {
+	sql := `UPDATE refresh_tokens SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, sql, userID, time.Now())
+	if err != nil {
+		r.log.Errorw("error on execute RevokeAllByUser", "error", err, "user_id", userID)
+		return err
+	}
+
+	return nil
+}
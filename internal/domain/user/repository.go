@@ -1,17 +1,27 @@
 package user
 
 import (
+	"context"
 	"database/sql"
 	"market/pkg/database"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type Repository interface {
-	FindByEmail(email string) (*User, error)
-	FindByID(id uuid.UUID) (*User, error)
-	Save(user *User) error
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	Save(ctx context.Context, user *User) error
+	UpdateRole(ctx context.Context, id uuid.UUID, role Role) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	// RecordLoginFailure increments the failed logins counter, returning the new value
+	RecordLoginFailure(ctx context.Context, id uuid.UUID) (int, error)
+	LockUntil(ctx context.Context, id uuid.UUID, until time.Time) error
+	// RecordLoginSuccess resets the failed logins counter and sets the last login
+	RecordLoginSuccess(ctx context.Context, id uuid.UUID, at time.Time) error
 }
 
 type userRepository struct {
@@ -27,9 +37,9 @@ func NewRepository(
 	dbInstance := database.GetInstance(log)
 
 	insert := `INSERT INTO public.users
-		(id, email, "password", "name", status, email_verified, last_login, created_at, updated_at)
+		(id, email, "password", "name", status, email_verified, last_login, role, created_at, updated_at)
 	VALUES
-		($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
+		($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
 
 	createStatment, err := dbInstance.Prepare(insert)
 	if err != nil {
@@ -42,10 +52,10 @@ func NewRepository(
 		createStatment: createStatment,
 	}
 }
-func (u *userRepository) FindByEmail(email string) (*User, error) {
-	sql := `SELECT id, email, "password", "name", status, email_verified, last_login, created_at, updated_at
+func (u *userRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	sql := `SELECT id, email, "password", "name", status, email_verified, last_login, failed_logins, locked_until, role, created_at, updated_at
 	FROM users WHERE email = $1 LIMIT 1`
-	row, err := u.db.Query(sql, email)
+	row, err := u.db.QueryContext(ctx, sql, email)
 
 	if err != nil {
 		u.log.Errorw("error on execute FindByEmail: %v", err)
@@ -64,6 +74,9 @@ func (u *userRepository) FindByEmail(email string) (*User, error) {
 			&user.Status,
 			&user.EmailVerified,
 			&user.LastLogin,
+			&user.FailedLogins,
+			&user.LockedUntil,
+			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -78,10 +91,10 @@ func (u *userRepository) FindByEmail(email string) (*User, error) {
 	return nil, nil
 }
 
-func (u *userRepository) FindByID(id uuid.UUID) (*User, error) {
-	sql := `SELECT id, email, "password", "name", status, email_verified, last_login, created_at, updated_at
+func (u *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*User, error) {
+	sql := `SELECT id, email, "password", "name", status, email_verified, last_login, failed_logins, locked_until, role, created_at, updated_at
 	FROM users WHERE id = $1 LIMIT 1`
-	row, err := u.db.Query(sql, id)
+	row, err := u.db.QueryContext(ctx, sql, id)
 
 	if err != nil {
 		u.log.Errorw("error on execute FindByID: %v", err)
@@ -100,6 +113,9 @@ func (u *userRepository) FindByID(id uuid.UUID) (*User, error) {
 			&user.Status,
 			&user.EmailVerified,
 			&user.LastLogin,
+			&user.FailedLogins,
+			&user.LockedUntil,
+			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -114,8 +130,9 @@ func (u *userRepository) FindByID(id uuid.UUID) (*User, error) {
 	return nil, nil
 }
 
-func (u *userRepository) Save(user *User) error {
-	_, err := u.createStatment.Exec(
+func (u *userRepository) Save(ctx context.Context, user *User) error {
+	_, err := u.db.Stmt(ctx, u.createStatment).ExecContext(
+		ctx,
 		user.ID,
 		user.Email,
 		user.Password,
@@ -123,6 +140,7 @@ func (u *userRepository) Save(user *User) error {
 		user.Status,
 		user.EmailVerified,
 		user.LastLogin,
+		user.Role,
 	)
 
 	if err != nil {
@@ -132,3 +150,75 @@ func (u *userRepository) Save(user *User) error {
 
 	return nil
 }
+
+func (u *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role Role) error {
+	sql := `UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := u.db.ExecContext(ctx, sql, id, role)
+	if err != nil {
+		u.log.Errorw("error on execute UpdateRole", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	sql := `UPDATE users SET "password" = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := u.db.ExecContext(ctx, sql, id, password)
+	if err != nil {
+		u.log.Errorw("error on execute UpdatePassword", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	sql := `UPDATE users SET email_verified = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := u.db.ExecContext(ctx, sql, id)
+	if err != nil {
+		u.log.Errorw("error on execute MarkEmailVerified", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) RecordLoginFailure(ctx context.Context, id uuid.UUID) (int, error) {
+	sql := `UPDATE users SET failed_logins = failed_logins + 1 WHERE id = $1 RETURNING failed_logins`
+
+	var failed int
+	if err := u.db.QueryRowContext(ctx, sql, id).Scan(&failed); err != nil {
+		u.log.Errorw("error on execute RecordLoginFailure", "error", err, "id", id)
+		return 0, err
+	}
+
+	return failed, nil
+}
+
+func (u *userRepository) LockUntil(ctx context.Context, id uuid.UUID, until time.Time) error {
+	sql := `UPDATE users SET locked_until = $2 WHERE id = $1`
+
+	_, err := u.db.ExecContext(ctx, sql, id, until)
+	if err != nil {
+		u.log.Errorw("error on execute LockUntil", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) RecordLoginSuccess(ctx context.Context, id uuid.UUID, at time.Time) error {
+	sql := `UPDATE users SET failed_logins = 0, locked_until = NULL, last_login = $2 WHERE id = $1`
+
+	_, err := u.db.ExecContext(ctx, sql, id, at)
+	if err != nil {
+		u.log.Errorw("error on execute RecordLoginSuccess", "error", err, "id", id)
+		return err
+	}
+
+	return nil
+}
@@ -1,9 +1,18 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"market/internal/domain/company"
+	"market/pkg/apperr"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/mailer"
 	"market/pkg/security"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -13,45 +22,70 @@ import (
 )
 
 var (
-	ErrPasswordMismatch = errors.New("password does not match")
-	ErrUserNotFound     = errors.New("user not found")
+	ErrPasswordMismatch    = apperr.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrEmailTaken          = apperr.Conflict("email_taken", "email already registered")
+	ErrUserNotFound        = apperr.NotFound("user_not_found", "user not found")
+	ErrInvalidRefreshToken = apperr.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	ErrInvalidRole         = apperr.Validation("invalid_role", "role must be admin, curator or member")
+	ErrInvalidToken        = apperr.Validation("invalid_token", "token is invalid, expired or already used")
+	ErrEmailVerified       = apperr.Conflict("email_already_verified", "email already verified")
+	ErrPasswordRequired    = apperr.Validation("password_required", "password is required")
+	ErrTooManyAttempts     = apperr.TooManyRequests("too_many_login_attempts", "too many login attempts")
 )
 
+// sessionsLimit caps the sign ins returned by Sessions
+const sessionsLimit = 20
+
+// dummyPassword is compared when the email is unknown, so the response time
+// does not tell registered emails apart
+var dummyPassword = sync.OnceValue(func() []byte {
+	hash, _ := security.CryptoPassword(security.NewOpaqueToken())
+	return hash
+})
+
 type service struct {
-	log        *zap.SugaredLogger
-	repository Repository
+	log                    *zap.SugaredLogger
+	db                     *database.PostgresDB
+	repository             Repository
+	companyService         company.UseCase
+	refreshTokenRepository RefreshTokenRepository
+	userTokenRepository    UserTokenRepository
+	loginAttemptRepository LoginAttemptRepository
+	mailer                 mailer.Mailer
+	policy                 LoginPolicy
 }
 
 func NewService(
 	log *zap.SugaredLogger,
 ) UseCase {
 	return &service{
-		log:        log,
-		repository: NewRepository(log),
+		log:                    log,
+		db:                     database.GetInstance(log),
+		repository:             NewRepository(log),
+		companyService:         company.NewService(log),
+		refreshTokenRepository: NewRefreshTokenRepository(log),
+		userTokenRepository:    NewUserTokenRepository(log),
+		loginAttemptRepository: NewLoginAttemptRepository(log),
+		mailer:                 mailer.New(log),
+		policy:                 LoginPolicyFromConfig(),
 	}
 }
 
-func (s *service) Register(input *UserCreateDTO) (*UserToken, error) {
-
-	err := input.Validate()
-	if err != nil {
-		return nil, fmt.Errorf("error", err)
-	}
-
-	userFound, err := s.repository.FindByEmail(input.Email)
+func (s *service) Register(ctx context.Context, input *UserCreateDTO) (*UserToken, error) {
+	userFound, err := s.repository.FindByEmail(ctx, input.Email)
 	if err != nil {
 		s.log.Errorw(err.Error())
 		return nil, err
 	}
 
 	if userFound != nil {
-		return nil, fmt.Errorf("email %s already exists", input.Email)
+		return nil, ErrEmailTaken
 	}
 
 	password, err := security.CryptoPassword(input.Password)
 
 	if err != nil {
-		return nil, fmt.Errorf("internal error")
+		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
 	newUser := &User{
@@ -62,93 +96,482 @@ func (s *service) Register(input *UserCreateDTO) (*UserToken, error) {
 		EmailVerified: false,
 		LastLogin:     nil,
 		Status:        UserStatusActive,
+		Role:          RoleMember,
 	}
 
-	err = s.repository.Save(newUser)
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.repository.Save(ctx, newUser); err != nil {
+			return err
+		}
+		_, err := s.companyService.CreatePersonal(ctx, newUser.ID, newUser.Name)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error on save new user")
+		return nil, fmt.Errorf("error on save new user: %w", err)
 	}
 
-	token, err := GenerateUserJWT(newUser)
-	if err != nil {
-		return nil, err
+	// The account works before the email is confirmed, so a mail failure only gets logged
+	if err := s.sendVerification(ctx, newUser); err != nil {
+		s.log.Errorw("error sending verification email", "error", err, "user_id", newUser.ID)
 	}
 
-	var userToken UserToken
-	return userToken.NewUserToken(newUser, token), nil
+	return s.issueTokens(ctx, newUser, nil)
 }
 
-func (s *service) Login(input *UserLoginDTO) (*UserToken, error) {
+// Login checks the credentials, throttling by IP and by account. Every attempt
+// is recorded so users can review the recent sign ins of their account.
+func (s *service) Login(ctx context.Context, input *UserLoginDTO, client ClientInfo) (*UserToken, error) {
+	now := time.Now()
 
-	userFound, err := s.repository.FindByEmail(input.Email)
+	if err := s.checkIPFailures(ctx, client.IP, now); err != nil {
+		return nil, err
+	}
+
+	userFound, err := s.repository.FindByEmail(ctx, input.Email)
 	if err != nil {
 		s.log.Errorw(err.Error())
 		return nil, err
 	}
 
 	if userFound == nil {
-		return nil, fmt.Errorf("user not exists")
+		bcrypt.CompareHashAndPassword(dummyPassword(), []byte(input.Password))
+		s.recordAttempt(ctx, nil, input.Email, client, false)
+		return nil, ErrPasswordMismatch
+	}
+
+	if userFound.Locked(now) {
+		s.recordAttempt(ctx, &userFound.ID, input.Email, client, false)
+		return nil, &TooManyAttemptsError{RetryAfter: userFound.LockedUntil.Sub(now)}
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(userFound.Password), []byte(input.Password))
 	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return nil, ErrPasswordMismatch
+		if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return nil, err
 		}
+		s.recordAttempt(ctx, &userFound.ID, input.Email, client, false)
+		return nil, s.recordFailure(ctx, userFound, now)
+	}
+
+	if err := s.repository.RecordLoginSuccess(ctx, userFound.ID, now); err != nil {
+		return nil, err
+	}
+	s.recordAttempt(ctx, &userFound.ID, input.Email, client, true)
+
+	return s.issueTokens(ctx, userFound, nil)
+}
+
+// checkIPFailures blocks the IP once it reaches the failures allowed in the window
+func (s *service) checkIPFailures(ctx context.Context, ip string, now time.Time) error {
+	if s.policy.IPMaxFailures <= 0 || ip == "" {
+		return nil
+	}
+
+	failures, oldest, err := s.loginAttemptRepository.FailuresByIP(ctx, ip, now.Add(-s.policy.IPWindow))
+	if err != nil {
+		return err
+	}
+	if failures < s.policy.IPMaxFailures || oldest == nil {
+		return nil
+	}
+
+	s.log.Warnw("login blocked for ip", "ip", ip, "failures", failures)
+	return &TooManyAttemptsError{RetryAfter: oldest.Add(s.policy.IPWindow).Sub(now)}
+}
+
+// recordFailure counts the failed login and locks the account as the policy says
+func (s *service) recordFailure(ctx context.Context, u *User, now time.Time) error {
+	failures, err := s.repository.RecordLoginFailure(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	until := s.policy.LockedUntil(failures, now)
+	if until == nil {
+		return ErrPasswordMismatch
+	}
+	if err := s.repository.LockUntil(ctx, u.ID, *until); err != nil {
+		return err
+	}
+
+	s.log.Warnw("account locked after failed logins", "user_id", u.ID, "failures", failures, "until", until)
+	return &TooManyAttemptsError{RetryAfter: until.Sub(now)}
+}
+
+// recordAttempt stores the sign in history, failures only get logged so the
+// history never prevents a login. Client values are cut to the column sizes,
+// an oversized header must not keep a failure from being counted.
+func (s *service) recordAttempt(ctx context.Context, userID *uuid.UUID, email string, client ClientInfo, success bool) {
+	err := s.loginAttemptRepository.Save(ctx, &LoginAttempt{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Email:     truncate(email, maxAttemptEmail),
+		IP:        truncate(client.IP, maxAttemptIP),
+		UserAgent: truncate(client.UserAgent, maxAttemptUserAgent),
+		Success:   success,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.log.Errorw("error recording login attempt", "error", err, "email", email)
+	}
+}
+
+// Sessions returns the recent sign ins of the user, newest first
+func (s *service) Sessions(ctx context.Context, userID uuid.UUID) ([]*LoginAttempt, error) {
+	return s.loginAttemptRepository.ListByUser(ctx, userID, sessionsLimit)
+}
+
+// Refresh exchanges a refresh token for a new pair, revoking the used one.
+// Presenting an already revoked token means it was stolen or replayed, so
+// every session of the user is revoked.
+func (s *service) Refresh(ctx context.Context, input *RefreshTokenDTO) (*UserToken, error) {
+	if input == nil || input.RefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	current, err := s.refreshTokenRepository.FindByHash(ctx, security.HashToken(input.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil {
+		s.log.Warnw("revoked refresh token reused, revoking all sessions", "user_id", current.UserID, "token_id", current.ID)
+		if err := s.refreshTokenRepository.RevokeAllByUser(ctx, current.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if !current.Active(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	userFound, err := s.repository.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if userFound == nil || userFound.Status != UserStatusActive {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// The user may have left the company since the token was issued
+	companyID, err := s.companyService.Resolve(ctx, userFound.ID, current.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userToken *UserToken
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		var refreshID uuid.UUID
+		userToken, refreshID, err = s.newTokens(ctx, userFound, companyID)
+		if err != nil {
+			return err
+		}
+		return s.refreshTokenRepository.Revoke(ctx, current.ID, &refreshID)
+	})
+	if err != nil {
+		s.log.Errorw("error rotating refresh token", "error", err, "user_id", userFound.ID)
+		return nil, err
+	}
+
+	return userToken, nil
+}
+
+// Logout revokes the refresh token, unknown tokens are ignored
+func (s *service) Logout(ctx context.Context, input *RefreshTokenDTO) error {
+	if input == nil || input.RefreshToken == "" {
+		return ErrInvalidRefreshToken
+	}
+
+	current, err := s.refreshTokenRepository.FindByHash(ctx, security.HashToken(input.RefreshToken))
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+
+	return s.refreshTokenRepository.Revoke(ctx, current.ID, nil)
+}
+
+// SwitchCompany issues tokens for another company of the user
+func (s *service) SwitchCompany(ctx context.Context, userID uuid.UUID, input *CompanySwitchDTO) (*UserToken, error) {
+	if input == nil || input.CompanyID == uuid.Nil {
+		return nil, company.ErrNotMember
+	}
+
+	found, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
+	if found == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return s.issueTokens(ctx, found, &input.CompanyID)
+}
 
-	token, err := GenerateUserJWT(userFound)
+// issueTokens signs tokens for the preferred company, or the first company of
+// the user when preferred is nil. Asking for a company the user does not
+// belong to fails with company.ErrNotMember.
+func (s *service) issueTokens(ctx context.Context, u *User, preferred *uuid.UUID) (*UserToken, error) {
+	companyID, err := s.companyService.Resolve(ctx, u.ID, preferred)
 	if err != nil {
 		return nil, err
 	}
+	if preferred != nil && companyID != *preferred {
+		return nil, company.ErrNotMember
+	}
+
+	userToken, _, err := s.newTokens(ctx, u, companyID)
+	return userToken, err
+}
+
+// newTokens signs an access token and stores a new refresh token for the user
+func (s *service) newTokens(ctx context.Context, u *User, companyID uuid.UUID) (*UserToken, uuid.UUID, error) {
+	token, err := GenerateUserJWT(u, companyID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	opaque := security.NewOpaqueToken()
+	refresh := &RefreshToken{
+		ID:        uuid.New(),
+		UserID:    u.ID,
+		CompanyID: &companyID,
+		TokenHash: security.HashToken(opaque),
+		ExpiresAt: time.Now().Add(config.Get().JWT_REFRESH_TTL),
+	}
+	if err := s.refreshTokenRepository.Save(ctx, refresh); err != nil {
+		return nil, uuid.Nil, fmt.Errorf("error saving refresh token: %w", err)
+	}
+	token.RefreshToken = opaque
 
 	var userToken UserToken
-	return userToken.NewUserToken(userFound, token), nil
+	return userToken.NewUserToken(u, token), refresh.ID, nil
 }
 
-func (s *service) Me(id uuid.UUID) (UserFoundDTO, error) {
-	found, err := s.repository.FindByID(id)
+func (s *service) Me(ctx context.Context, id uuid.UUID) (UserFoundDTO, error) {
+	found, err := s.repository.FindByID(ctx, id)
 	if err != nil {
-		return UserFoundDTO{}, fmt.Errorf("user not exists by id")
+		return UserFoundDTO{}, err
+	}
+	if found == nil {
+		return UserFoundDTO{}, ErrUserNotFound
 	}
 
 	userFoundDTO := UserFoundDTO{
-		ID:        found.ID,
-		Name:      found.Name,
-		Email:     found.Email,
-		CreatedAt: found.CreatedAt,
-		UpdatedAt: found.CreatedAt,
+		ID:            found.ID,
+		Name:          found.Name,
+		Email:         found.Email,
+		Role:          found.Role,
+		EmailVerified: found.EmailVerified,
+		CreatedAt:     found.CreatedAt,
+		UpdatedAt:     found.CreatedAt,
 	}
 
 	return userFoundDTO, nil
 }
 
-func GenerateUserJWT(u *User) (*Token, error) {
+// ChangeRole sets the role of the user. The new role reaches the token on the
+// next refresh, so it applies within JWT_ACCESS_TTL.
+func (s *service) ChangeRole(ctx context.Context, id uuid.UUID, input *RoleUpdateDTO) error {
+	if input == nil || !input.Role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	found, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.repository.UpdateRole(ctx, id, input.Role); err != nil {
+		s.log.Errorw("error changing user role", "error", err, "id", id)
+		return err
+	}
 
-	expirationTime := time.Now().Add(8 * time.Hour) // Token expira em 8 horas
+	s.log.Infow("user role changed", "id", id, "from", found.Role, "to", input.Role)
+	return nil
+}
+
+// RequestEmailVerification sends a new verification link, invalidating the previous ones
+func (s *service) RequestEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	found, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return ErrUserNotFound
+	}
+	if found.EmailVerified {
+		return ErrEmailVerified
+	}
+
+	return s.sendVerification(ctx, found)
+}
+
+func (s *service) VerifyEmail(ctx context.Context, token string) error {
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		record, err := s.consumeToken(ctx, TokenPurposeEmailVerification, token)
+		if err != nil {
+			return err
+		}
+		return s.repository.MarkEmailVerified(ctx, record.UserID)
+	})
+}
+
+// ForgotPassword sends a reset link when the email belongs to an active user.
+// Unknown emails succeed too, so the endpoint does not reveal who is registered.
+func (s *service) ForgotPassword(ctx context.Context, input *PasswordForgotDTO) error {
+	if input == nil || strings.TrimSpace(input.Email) == "" {
+		return nil
+	}
+
+	found, err := s.repository.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return err
+	}
+	if found == nil || found.Status != UserStatusActive {
+		s.log.Infow("password reset requested for unknown or inactive email")
+		return nil
+	}
+
+	token, err := s.newEmailToken(ctx, found.ID, TokenPurposePasswordReset, config.Get().PASSWORD_RESET_TTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      found.Email,
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nPara criar uma nova senha acesse o link abaixo:\n\n%s/reset-password?token=%s\n\nO link expira em %s. Se você não pediu a redefinição, ignore este e-mail.\n",
+			found.Name, config.Get().APP_BASE_URL, url.QueryEscape(token), config.Get().PASSWORD_RESET_TTL,
+		),
+	})
+}
+
+// ResetPassword sets the new password and ends every session of the user
+func (s *service) ResetPassword(ctx context.Context, input *PasswordResetDTO) error {
+	if input == nil || strings.TrimSpace(input.Password) == "" {
+		return ErrPasswordRequired
+	}
+
+	password, err := security.CryptoPassword(input.Password)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		record, err := s.consumeToken(ctx, TokenPurposePasswordReset, input.Token)
+		if err != nil {
+			return err
+		}
+		if err := s.repository.UpdatePassword(ctx, record.UserID, string(password)); err != nil {
+			return err
+		}
+		s.log.Infow("password reset", "user_id", record.UserID)
+		return s.refreshTokenRepository.RevokeAllByUser(ctx, record.UserID)
+	})
+}
+
+func (s *service) sendVerification(ctx context.Context, u *User) error {
+	token, err := s.newEmailToken(ctx, u.ID, TokenPurposeEmailVerification, config.Get().EMAIL_VERIFICATION_TTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Confirme seu e-mail",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nConfirme seu e-mail acessando o link abaixo:\n\n%s/auth/verify-email?token=%s\n\nO link expira em %s.\n",
+			u.Name, config.Get().API_BASE_URL, url.QueryEscape(token), config.Get().EMAIL_VERIFICATION_TTL,
+		),
+	})
+}
+
+// newEmailToken stores the hash of a new single use token and returns the token
+func (s *service) newEmailToken(ctx context.Context, userID uuid.UUID, purpose TokenPurpose, ttl time.Duration) (string, error) {
+	token := security.NewOpaqueToken()
+
+	err := s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.userTokenRepository.InvalidateAll(ctx, userID, purpose); err != nil {
+			return err
+		}
+		return s.userTokenRepository.Save(ctx, &UserTokenRecord{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: security.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		})
+	})
+	if err != nil {
+		s.log.Errorw("error creating user token", "error", err, "user_id", userID, "purpose", purpose)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeToken marks the token as used, failing when it cannot be used anymore
+func (s *service) consumeToken(ctx context.Context, purpose TokenPurpose, token string) (*UserTokenRecord, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	record, err := s.userTokenRepository.FindByHash(ctx, purpose, security.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.Usable(time.Now()) {
+		return nil, ErrInvalidToken
+	}
+
+	used, err := s.userTokenRepository.Use(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidToken
+	}
+
+	return record, nil
+}
+
+// GenerateUserJWT signs a short lived access token for the company with the active key
+func GenerateUserJWT(u *User, companyID uuid.UUID) (*Token, error) {
+	now := time.Now()
+	ttl := config.Get().JWT_ACCESS_TTL
 
 	claims := &Claims{
-		Email:  u.Email,
-		UserID: u.ID,
+		Email:     u.Email,
+		Role:      u.Role,
+		UserID:    u.ID,
+		CompanyID: companyID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(), // Tempo de expiração
-			IssuedAt:  time.Now().Unix(),     // Tempo de emissão
+			Id:        uuid.NewString(),
+			Subject:   u.ID.String(),
+			Issuer:    config.Get().JWT_ISSUER,
+			ExpiresAt: now.Add(ttl).Unix(), // Tempo de expiração
+			IssuedAt:  now.Unix(),          // Tempo de emissão
 		},
 	}
 
-	// Cria o token JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte("sua_chave_secreta_aqui"))
+	tokenString, err := security.Keys().Sign(claims)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	expirationTimeSeconds := int64((8 * time.Hour).Seconds())
-
 	return &Token{
 		AccessToken: tokenString,
-		ExpiresAt:   expirationTimeSeconds,
+		ExpiresAt:   int64(ttl.Seconds()),
 		TokenType:   "Bearer",
 	}, nil
 }
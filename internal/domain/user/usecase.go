@@ -1,11 +1,22 @@
 package user
 
 import (
+	"context"
 	"github.com/google/uuid"
 )
 
 type UseCase interface {
-	Register(input *UserCreateDTO) (*UserToken, error)
-	Login(input *UserLoginDTO) (*UserToken, error)
-	Me(id uuid.UUID) (UserFoundDTO, error)
+	Register(ctx context.Context, input *UserCreateDTO) (*UserToken, error)
+	Login(ctx context.Context, input *UserLoginDTO, client ClientInfo) (*UserToken, error)
+	Refresh(ctx context.Context, input *RefreshTokenDTO) (*UserToken, error)
+	Logout(ctx context.Context, input *RefreshTokenDTO) error
+	SwitchCompany(ctx context.Context, userID uuid.UUID, input *CompanySwitchDTO) (*UserToken, error)
+	Me(ctx context.Context, id uuid.UUID) (UserFoundDTO, error)
+	Sessions(ctx context.Context, userID uuid.UUID) ([]*LoginAttempt, error)
+	ChangeRole(ctx context.Context, id uuid.UUID, input *RoleUpdateDTO) error
+
+	RequestEmailVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	ForgotPassword(ctx context.Context, input *PasswordForgotDTO) error
+	ResetPassword(ctx context.Context, input *PasswordResetDTO) error
 }
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"market/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type UserTokenRepository interface {
+	Save(ctx context.Context, token *UserTokenRecord) error
+	FindByHash(ctx context.Context, purpose TokenPurpose, hash string) (*UserTokenRecord, error)
+	Use(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateAll(ctx context.Context, userID uuid.UUID, purpose TokenPurpose) error
+}
+
+type userTokenRepository struct {
+	db              *database.PostgresDB
+	log             *zap.SugaredLogger
+	createStatement *sql.Stmt
+}
+
+func NewUserTokenRepository(log *zap.SugaredLogger) UserTokenRepository {
+	dbInstance := database.GetInstance(log)
+
+	insert := `INSERT INTO user_tokens
+		(id, user_id, purpose, token_hash, expires_at, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP);`
+
+	createStatement, err := dbInstance.Prepare(insert)
+	if err != nil {
+		log.Errorw("error on create statement", "error", err)
+	}
+
+	return &userTokenRepository{
+		db:              dbInstance,
+		log:             log,
+		createStatement: createStatement,
+	}
+}
+
+func (r *userTokenRepository) Save(ctx context.Context, token *UserTokenRecord) error {
+	_, err := r.db.Stmt(ctx, r.createStatement).ExecContext(
+		ctx,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		r.log.Errorw("error on execute Save", "error", err, "user_id", token.UserID)
+		return err
+	}
+
+	return nil
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, purpose TokenPurpose, hash string) (*UserTokenRecord, error) {
+	sql := `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at
+	FROM user_tokens WHERE purpose = $1 AND token_hash = $2 LIMIT 1`
+
+	rows, err := r.db.QueryContext(ctx, sql, purpose, hash)
+	if err != nil {
+		r.log.Errorw("error on execute FindByHash", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	var token UserTokenRecord
+	err = rows.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		r.log.Errorw("error on scan FindByHash", "error", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Use marks the token as used, returning false when another request used it first
+func (r *userTokenRepository) Use(ctx context.Context, id uuid.UUID) (bool, error) {
+	sql := `UPDATE user_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, sql, id, time.Now())
+	if err != nil {
+		r.log.Errorw("error on execute Use", "error", err, "id", id)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// InvalidateAll expires the pending tokens of the user, so only the last one sent works
+func (r *userTokenRepository) InvalidateAll(ctx context.Context, userID uuid.UUID, purpose TokenPurpose) error {
+	sql := `UPDATE user_tokens SET used_at = $3
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, sql, userID, purpose, time.Now())
+	if err != nil {
+		r.log.Errorw("error on execute InvalidateAll", "error", err, "user_id", userID)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package ingestion
+
+import (
+	"market/pkg/providers"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// categoryResolver finds the internal category of offers using the provider
+// category mappings and remembers the external categories left unmapped
+type categoryResolver struct {
+	mapped   map[string]uuid.UUID
+	unmapped map[string]bool
+}
+
+func newCategoryResolver(mapped map[string]uuid.UUID) *categoryResolver {
+	return &categoryResolver{
+		mapped:   mapped,
+		unmapped: map[string]bool{},
+	}
+}
+
+// resolve tries the offer category paths from the most specific one and then
+// the crawled category ID. When none is mapped both the most specific path and
+// the crawled ID are recorded, either one can be mapped.
+func (c *categoryResolver) resolve(category providers.Category, offer *providers.Offer) *uuid.UUID {
+	for _, path := range offer.CategoryPaths {
+		if categoryID, ok := c.mapped[path]; ok {
+			return &categoryID
+		}
+	}
+
+	if categoryID, ok := c.mapped[category.ExternalID]; ok {
+		return &categoryID
+	}
+
+	if len(offer.CategoryPaths) > 0 {
+		c.unmapped[offer.CategoryPaths[0]] = true
+	}
+	if category.ExternalID != "" {
+		c.unmapped[category.ExternalID] = true
+	}
+	return nil
+}
+
+// unmappedIDs returns the category paths and IDs without mapping, sorted
+func (c *categoryResolver) unmappedIDs() []string {
+	ids := make([]string, 0, len(c.unmapped))
+	for id := range c.unmapped {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,29 @@
+package ingestion
+
+import (
+	"market/pkg/providers"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCategoryResolver(t *testing.T) {
+	frango := uuid.New()
+	resolver := newCategoryResolver(map[string]uuid.UUID{"/Carnes, Aves e Peixes/Frango/": frango})
+	crawled := providers.Category{ExternalID: "12"}
+
+	mapped := &providers.Offer{CategoryPaths: []string{"/Carnes, Aves e Peixes/Frango/Coxa/", "/Carnes, Aves e Peixes/Frango/"}}
+	if got := resolver.resolve(crawled, mapped); got == nil || *got != frango {
+		t.Fatalf("resolve = %v, want %v", got, frango)
+	}
+
+	unmapped := &providers.Offer{CategoryPaths: []string{"/Bebidas/Sucos/", "/Bebidas/"}}
+	if got := resolver.resolve(crawled, unmapped); got != nil {
+		t.Fatalf("resolve = %v, want nil", got)
+	}
+
+	if want := []string{"/Bebidas/Sucos/", "12"}; !reflect.DeepEqual(resolver.unmappedIDs(), want) {
+		t.Fatalf("unmapped = %v, want %v", resolver.unmappedIDs(), want)
+	}
+}
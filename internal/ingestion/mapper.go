@@ -0,0 +1,59 @@
+package ingestion
+
+import (
+	"market/internal/domain/product"
+	"market/internal/domain/product_market"
+	"market/pkg/providers"
+
+	"github.com/google/uuid"
+)
+
+// toProduct maps the offer to our catalog product, without category when
+// the offer category is not mapped
+func toProduct(offer *providers.Offer, categoryID *uuid.UUID) *product.Product {
+	var imageURL *string
+	if offer.ImageURL != "" {
+		url := offer.ImageURL
+		imageURL = &url
+	}
+
+	return &product.Product{
+		CategoryID: categoryID,
+		ImageURL:   imageURL,
+		Name:       truncate(offer.Name, 100),
+		Status:     product.ProductStatusActive,
+	}
+}
+
+// toProductMarket maps the offer to a product market price. When the selling
+// price is lower than the list price the difference is stored as a promotion.
+func toProductMarket(offer *providers.Offer, productID, marketID uuid.UUID) *product_market.ProductMarket {
+	providerID := offer.ProviderID
+	productMarket := &product_market.ProductMarket{
+		ProviderID: &providerID,
+		ProductID:  productID,
+		MarketID:   marketID,
+		Price:      offer.Price,
+		Status:     product_market.ProductMarketStatusInactive,
+	}
+
+	if offer.ListPrice > offer.Price && offer.Price > 0 {
+		price := offer.Price
+		productMarket.Price = offer.ListPrice
+		productMarket.PromotionalPrice = &price
+	}
+
+	if offer.Available {
+		productMarket.Status = product_market.ProductMarketStatusActive
+	}
+
+	return productMarket
+}
+
+func truncate(value string, size int) string {
+	runes := []rune(value)
+	if len(runes) <= size {
+		return value
+	}
+	return string(runes[:size])
+}
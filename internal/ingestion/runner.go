@@ -0,0 +1,271 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"market/internal/domain/category_mapping"
+	"market/internal/domain/price_history"
+	"market/internal/domain/product"
+	"market/internal/domain/product_market"
+	"market/internal/domain/sync_run"
+	"market/pkg/database"
+	"market/pkg/providers"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Report summarizes a provider ingestion run
+type Report struct {
+	Provider string `json:"provider"`
+	Pages    int    `json:"pages"`
+	Fetched  int    `json:"fetched"`
+	Saved    int    `json:"saved"`
+	Failed   int    `json:"failed"`
+	DryRun   bool   `json:"dry_run"`
+	// Unmapped lists the category paths and IDs without internal category
+	Unmapped   []string  `json:"unmapped"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// LockProvider takes the database lock that keeps one sync per provider at a
+// time across the scheduler, the sync command and every replica. It fails with
+// sync_run.ErrAlreadyRunning when another sync holds it.
+func LockProvider(ctx context.Context, db *database.PostgresDB, provider string) (release func(), err error) {
+	release, err = db.TryLock(ctx, "sync:"+provider)
+	if errors.Is(err, database.ErrLocked) {
+		return nil, sync_run.ErrAlreadyRunning
+	}
+	return release, err
+}
+
+// Runner pulls the catalog of a provider and upserts it into products and
+// product_markets. With DryRun set the catalog is fetched and mapped but
+// nothing is written, Saved then counts the offers that would be stored.
+type Runner struct {
+	PageDelay                 time.Duration
+	DryRun                    bool
+	log                       *zap.SugaredLogger
+	db                        *database.PostgresDB
+	productRepository         product.Repository
+	productMarketRepository   product_market.Repository
+	priceHistoryRepository    price_history.Repository
+	categoryMappingRepository category_mapping.Repository
+}
+
+func NewRunner(log *zap.SugaredLogger) *Runner {
+	return &Runner{
+		PageDelay:                 200 * time.Millisecond,
+		log:                       log,
+		db:                        database.GetInstance(log),
+		productRepository:         product.NewRepository(log),
+		productMarketRepository:   product_market.NewRepository(log),
+		priceHistoryRepository:    price_history.NewRepository(log),
+		categoryMappingRepository: category_mapping.NewRepository(log),
+	}
+}
+
+// Run crawls every category of the provider page by page until an empty page
+// is returned. Failures on single offers are logged and counted so one bad
+// item does not abort the sync. Categories without mapping are reported and
+// registered as unmapped so operators can map them.
+func (r *Runner) Run(ctx context.Context, provider providers.Provider) (*Report, error) {
+	report := &Report{
+		Provider:  provider.Name(),
+		DryRun:    r.DryRun,
+		StartedAt: time.Now(),
+	}
+
+	mapped, err := r.categoryMappingRepository.FindMapped(ctx, provider.Name())
+	if err != nil {
+		return report, err
+	}
+
+	resolver := newCategoryResolver(mapped)
+	defer func() {
+		report.FinishedAt = time.Now()
+		report.Unmapped = resolver.unmappedIDs()
+		if len(report.Unmapped) > 0 {
+			r.log.Warnw("provider categories without mapping", "provider", provider.Name(), "categories", report.Unmapped)
+			if r.DryRun {
+				return
+			}
+			if err := r.categoryMappingRepository.SaveUnmapped(context.WithoutCancel(ctx), provider.Name(), report.Unmapped); err != nil {
+				r.log.Errorw("error saving unmapped categories", "error", err, "provider", provider.Name())
+			}
+		}
+	}()
+
+	for _, category := range provider.Categories() {
+		for page := 0; ; page++ {
+			offers, err := provider.FetchProducts(ctx, category, page)
+			if err != nil {
+				return report, err
+			}
+
+			if len(offers) == 0 {
+				break
+			}
+
+			report.Pages++
+			report.Fetched += len(offers)
+			for i := range offers {
+				categoryID := resolver.resolve(category, &offers[i])
+				saved, err := r.saveOffer(ctx, provider, categoryID, &offers[i])
+				if err != nil {
+					report.Failed++
+					r.log.Errorw("error saving offer", "error", err, "provider", provider.Name(), "provider_id", offers[i].ProviderID)
+					continue
+				}
+				if saved {
+					report.Saved++
+				}
+			}
+
+			select {
+			case <-time.After(r.PageDelay):
+			case <-ctx.Done():
+				return report, ctx.Err()
+			}
+		}
+	}
+
+	r.log.Infow("provider synced",
+		"provider", report.Provider,
+		"pages", report.Pages,
+		"fetched", report.Fetched,
+		"saved", report.Saved,
+		"failed", report.Failed,
+		"dry_run", report.DryRun,
+	)
+	return report, nil
+}
+
+// saveOffer stores the offer in a single transaction, so a product is never
+// left without its market price when the sync fails halfway
+func (r *Runner) saveOffer(ctx context.Context, provider providers.Provider, categoryID *uuid.UUID, offer *providers.Offer) (bool, error) {
+	if r.DryRun {
+		return r.checkOffer(ctx, provider, categoryID, offer)
+	}
+
+	var saved bool
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		saved, err = r.upsertOffer(ctx, provider, categoryID, offer)
+		return err
+	})
+	return saved, err
+}
+
+// upsertOffer creates the product and its market price on the first sync and
+// refreshes them on the next ones, using the provider product ID as the key.
+// Every new or changed price is also recorded in the price history.
+func (r *Runner) upsertOffer(ctx context.Context, provider providers.Provider, categoryID *uuid.UUID, offer *providers.Offer) (bool, error) {
+	marketID := provider.MarketID()
+	existing, err := r.productMarketRepository.FindByMarketAndProviderID(ctx, marketID, offer.ProviderID)
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		productMarket := toProductMarket(offer, uuid.Nil, marketID)
+		// Nothing worth storing for products that were never sold here
+		if productMarket.Price <= 0 {
+			return false, nil
+		}
+
+		newProduct, err := r.productRepository.Save(ctx, toProduct(offer, categoryID))
+		if err != nil {
+			return false, err
+		}
+
+		productMarket.ID = uuid.New()
+		productMarket.ProductID = newProduct.ID
+		if _, err = r.productMarketRepository.Save(ctx, productMarket); err != nil {
+			return false, err
+		}
+
+		return true, r.recordPrice(ctx, provider, productMarket)
+	}
+
+	currentProduct, err := r.productRepository.FindByID(ctx, existing.ProductID)
+	if err != nil {
+		return false, err
+	}
+
+	// Products deleted in the catalog are kept deleted, and fields a curator
+	// edited survive the next sync
+	if currentProduct != nil && refreshProduct(currentProduct, toProduct(offer, categoryID)) {
+		if _, err = r.productRepository.Update(ctx, currentProduct); err != nil {
+			return false, err
+		}
+	}
+
+	productMarket := toProductMarket(offer, existing.ProductID, marketID)
+	priceChanged := productMarket.Price > 0 && price_history.Changed(
+		existing.Price, existing.PromotionalPrice,
+		productMarket.Price, productMarket.PromotionalPrice,
+	)
+	if productMarket.Price > 0 {
+		existing.Price = productMarket.Price
+		existing.PromotionalPrice = productMarket.PromotionalPrice
+	}
+	existing.Status = productMarket.Status
+
+	if _, err = r.productMarketRepository.Update(ctx, existing); err != nil {
+		return false, err
+	}
+
+	if priceChanged {
+		return true, r.recordPrice(ctx, provider, existing)
+	}
+	return true, nil
+}
+
+// refreshProduct copies the provider fields a curator has not edited and
+// tells whether anything changed
+func refreshProduct(current, offer *product.Product) bool {
+	changed := false
+	if offer.Name != "" && offer.Name != current.Name && !current.Curated(product.FieldName) {
+		current.Name = offer.Name
+		changed = true
+	}
+	if offer.ImageURL != nil && !equalPtr(offer.ImageURL, current.ImageURL) && !current.Curated(product.FieldImageURL) {
+		current.ImageURL = offer.ImageURL
+		changed = true
+	}
+	if offer.CategoryID != nil && !equalPtr(offer.CategoryID, current.CategoryID) && !current.Curated(product.FieldCategoryID) {
+		current.CategoryID = offer.CategoryID
+		changed = true
+	}
+	return changed
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	return a == b || (a != nil && b != nil && *a == *b)
+}
+
+// checkOffer tells whether upsertOffer would store the offer without writing anything
+func (r *Runner) checkOffer(ctx context.Context, provider providers.Provider, _ *uuid.UUID, offer *providers.Offer) (bool, error) {
+	existing, err := r.productMarketRepository.FindByMarketAndProviderID(ctx, provider.MarketID(), offer.ProviderID)
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		return toProductMarket(offer, uuid.Nil, provider.MarketID()).Price > 0, nil
+	}
+	return true, nil
+}
+
+func (r *Runner) recordPrice(ctx context.Context, provider providers.Provider, productMarket *product_market.ProductMarket) error {
+	return r.priceHistoryRepository.Save(ctx, price_history.NewPriceHistory(
+		productMarket.ProductID,
+		productMarket.MarketID,
+		productMarket.Price,
+		productMarket.PromotionalPrice,
+		provider.Name(),
+	))
+}
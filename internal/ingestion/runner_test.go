@@ -0,0 +1,30 @@
+package ingestion
+
+import (
+	"market/internal/domain/product"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRefreshProduct(t *testing.T) {
+	oldImage, newImage := "old.png", "new.png"
+	category := uuid.New()
+	current := &product.Product{Name: "Arroz 5kg", ImageURL: &oldImage}
+	current.MarkCurated(product.FieldName)
+
+	offer := &product.Product{Name: "ARROZ TIPO 1 5KG", ImageURL: &newImage, CategoryID: &category}
+	if !refreshProduct(current, offer) {
+		t.Fatal("refreshProduct = false, want true")
+	}
+	if current.Name != "Arroz 5kg" {
+		t.Fatalf("name = %q, want the curated one", current.Name)
+	}
+	if *current.ImageURL != newImage || current.CategoryID == nil || *current.CategoryID != category {
+		t.Fatalf("image = %v, category = %v, want the provider ones", *current.ImageURL, current.CategoryID)
+	}
+
+	if refreshProduct(current, offer) {
+		t.Fatal("refreshProduct = true on the same offer, want false")
+	}
+}
@@ -1,19 +1,42 @@
 package routes
 
 import (
+	"market/internal/domain/api_key"
 	"market/internal/domain/attachment"
+	"market/internal/domain/basket"
+	"market/internal/domain/category_mapping"
+	"market/internal/domain/company"
+	"market/internal/domain/market"
+	"market/internal/domain/price_history"
 	"market/internal/domain/product"
 	"market/internal/domain/product_market"
+	"market/internal/domain/shopping_list"
+	"market/internal/domain/sync_run"
 	"market/internal/domain/user"
+	"market/pkg/database"
+	"market/pkg/health"
 	"market/pkg/middleware"
+	"market/pkg/security"
 	"net/http"
 
 	"github.com/rs/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func Auth(handler http.HandlerFunc) http.HandlerFunc {
-	return middleware.AuthMiddleware(handler)
+// Auth lets in signed in users, and API keys granted one of the scopes
+func Auth(handler http.HandlerFunc, scopes ...security.Scope) http.HandlerFunc {
+	return middleware.AuthMiddleware(handler, scopes...)
+}
+
+// Curator restricts catalog maintenance to curators and admins.
+// Curators add to the shared catalog, only admins change or remove what every company sees.
+func Curator(handler http.HandlerFunc, scopes ...security.Scope) http.HandlerFunc {
+	return Auth(middleware.RequireRole(handler, user.RoleCurator), scopes...)
+}
+
+// Admin restricts sync, user administration, category mappings and edits to the shared catalog to admins
+func Admin(handler http.HandlerFunc, scopes ...security.Scope) http.HandlerFunc {
+	return Auth(middleware.RequireRole(handler, user.RoleAdmin), scopes...)
 }
 
 func NewRoutes(
@@ -21,30 +44,116 @@ func NewRoutes(
 	productHandler *product.Handler,
 	productMarketHandler *product_market.Handler,
 	attachmentHandler *attachment.Handler,
+	priceHistoryHandler *price_history.Handler,
+	syncRunHandler *sync_run.Handler,
+	categoryMappingHandler *category_mapping.Handler,
+	marketHandler *market.Handler,
+	basketHandler *basket.Handler,
+	shoppingListHandler *shopping_list.Handler,
+	companyHandler *company.Handler,
+	apiKeyHandler *api_key.Handler,
+	db *database.PostgresDB,
+	healthHandler *health.Handler,
 ) http.Handler {
 	mux := http.NewServeMux()
 
 	// swagger route
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
 
+	// probe routes, no auth so orchestrators can reach them
+	mux.Handle("GET /healthz", middleware.HealthCheckMiddleware(http.HandlerFunc(healthHandler.LivenessHandler)))
+	mux.Handle("GET /readyz", middleware.HealthCheckMiddleware(http.HandlerFunc(healthHandler.ReadinessHandler)))
+
 	// auth routes
 	mux.HandleFunc("POST /auth/login", userHandler.LoginHandler)
 	mux.HandleFunc("POST /auth/register", userHandler.CreateUserHandler)
+	mux.HandleFunc("POST /auth/refresh", userHandler.RefreshHandler)
+	mux.HandleFunc("POST /auth/logout", userHandler.LogoutHandler)
+	mux.HandleFunc("POST /auth/verify-email/request", Auth(userHandler.RequestEmailVerificationHandler))
+	mux.HandleFunc("GET /auth/verify-email", userHandler.VerifyEmailHandler)
+	mux.HandleFunc("POST /auth/password/forgot", userHandler.ForgotPasswordHandler)
+	mux.HandleFunc("POST /auth/password/reset", userHandler.ResetPasswordHandler)
 	mux.HandleFunc("GET /auth/me", Auth(userHandler.MeHandler))
+	mux.HandleFunc("GET /auth/me/sessions", Auth(userHandler.SessionsHandler))
+	mux.HandleFunc("GET /auth/me/companies", Auth(companyHandler.MyCompaniesHandler))
+	mux.HandleFunc("POST /auth/switch-company", Auth(userHandler.SwitchCompanyHandler))
 
 	// product routes - clean REST endpoints
-	mux.HandleFunc("GET /products/{id}", Auth(productHandler.GetProductHandler))
+	mux.HandleFunc("POST /products", Curator(productHandler.CreateProductHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /products", Auth(productHandler.ListProductsHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /products/{id}", Auth(productHandler.GetProductHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("PUT /products/{id}", Admin(productHandler.UpdateProductHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("DELETE /products/{id}", Admin(productHandler.DeleteProductHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /products/{id}/price-history", Auth(priceHistoryHandler.GetPriceHistoryHandler, security.ScopePricesRead))
+
+	// category routes
+	mux.HandleFunc("POST /categories", Curator(productHandler.CreateCategoryHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /categories", Auth(productHandler.ListCategoriesHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /categories/{id}", Auth(productHandler.GetCategoryHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("PUT /categories/{id}", Curator(productHandler.UpdateCategoryHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("DELETE /categories/{id}", Curator(productHandler.DeleteCategoryHandler, security.ScopeCatalogWrite))
+
+	// market routes
+	mux.HandleFunc("POST /markets", Curator(marketHandler.CreateMarketHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /markets", Auth(marketHandler.ListMarketsHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /markets/nearby", Auth(marketHandler.NearbyStoresHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /markets/{id}", Auth(marketHandler.GetMarketHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("PUT /markets/{id}", Admin(marketHandler.UpdateMarketHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("DELETE /markets/{id}", Admin(marketHandler.DeleteMarketHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("POST /markets/{id}/stores", Curator(marketHandler.CreateStoreHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /markets/{id}/stores", Auth(marketHandler.ListStoresHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /markets/{id}/stores/{store_id}", Auth(marketHandler.GetStoreHandler, security.ScopeCatalogRead))
+	mux.HandleFunc("PUT /markets/{id}/stores/{store_id}", Admin(marketHandler.UpdateStoreHandler, security.ScopeCatalogWrite))
+	mux.HandleFunc("DELETE /markets/{id}/stores/{store_id}", Admin(marketHandler.DeleteStoreHandler, security.ScopeCatalogWrite))
+
+	// basket routes
+	mux.HandleFunc("POST /baskets/compare", Auth(basketHandler.CompareBasketHandler, security.ScopePricesRead))
+
+	// shopping list routes
+	mux.HandleFunc("POST /shopping-lists", Auth(shoppingListHandler.CreateListHandler))
+	mux.HandleFunc("GET /shopping-lists", Auth(shoppingListHandler.ListListsHandler))
+	mux.HandleFunc("GET /shopping-lists/{id}", Auth(shoppingListHandler.GetListHandler))
+	mux.HandleFunc("PUT /shopping-lists/{id}", Auth(shoppingListHandler.UpdateListHandler))
+	mux.HandleFunc("DELETE /shopping-lists/{id}", Auth(shoppingListHandler.DeleteListHandler))
+	mux.HandleFunc("POST /shopping-lists/{id}/items", Auth(shoppingListHandler.AddItemHandler))
+	mux.HandleFunc("PUT /shopping-lists/{id}/items/{item_id}", Auth(shoppingListHandler.UpdateItemHandler))
+	mux.HandleFunc("DELETE /shopping-lists/{id}/items/{item_id}", Auth(shoppingListHandler.RemoveItemHandler))
+	mux.HandleFunc("GET /shopping-lists/{id}/members", Auth(shoppingListHandler.ListMembersHandler))
+	mux.HandleFunc("POST /shopping-lists/{id}/members", Auth(shoppingListHandler.ShareListHandler))
+	mux.HandleFunc("DELETE /shopping-lists/{id}/members/{user_id}", Auth(shoppingListHandler.UnshareListHandler))
 
 	// product market routes
-	mux.HandleFunc("POST /product-markets", Auth(productMarketHandler.CreateProductMarketHandler))
-	mux.HandleFunc("GET /product-markets/provider/{provider_id}", Auth(productMarketHandler.GetProductMarketsByProviderIDHandler))
+	mux.HandleFunc("POST /product-markets", Curator(productMarketHandler.CreateProductMarketHandler, security.ScopePricesWrite))
+	mux.HandleFunc("GET /product-markets", Auth(productMarketHandler.ListProductMarketsHandler, security.ScopePricesRead))
+	mux.HandleFunc("GET /product-markets/provider/{provider_id}", Auth(productMarketHandler.GetProductMarketsByProviderIDHandler, security.ScopePricesRead))
+
+	// api key routes, keys are managed by people only
+	mux.HandleFunc("POST /api-keys", Curator(apiKeyHandler.CreateAPIKeyHandler))
+	mux.HandleFunc("GET /api-keys", Curator(apiKeyHandler.ListAPIKeysHandler))
+	mux.HandleFunc("DELETE /api-keys/{id}", Curator(apiKeyHandler.RevokeAPIKeyHandler))
 
 	// attachment routes
-	mux.HandleFunc("POST /attachments", Auth(attachmentHandler.UploadAttachment))
-	mux.HandleFunc("GET /attachments/{id}", Auth(attachmentHandler.GetAttachmentByID))
-	mux.HandleFunc("PUT /attachments/{id}", Auth(attachmentHandler.UpdateAttachment))
-	mux.HandleFunc("PATCH /attachments/{id}", Auth(attachmentHandler.UpdateAttachment))
-	mux.HandleFunc("DELETE /attachments/{id}", Auth(attachmentHandler.DeleteAttachment))
+	mux.HandleFunc("POST /attachments", Curator(attachmentHandler.UploadAttachment, security.ScopeCatalogWrite))
+	mux.HandleFunc("GET /attachments", Auth(attachmentHandler.ListAttachments, security.ScopeCatalogRead))
+	mux.HandleFunc("GET /attachments/{id}", Auth(attachmentHandler.GetAttachmentByID, security.ScopeCatalogRead))
+	mux.HandleFunc("PUT /attachments/{id}", Curator(attachmentHandler.UpdateAttachment, security.ScopeCatalogWrite))
+	mux.HandleFunc("PATCH /attachments/{id}", Curator(attachmentHandler.UpdateAttachment, security.ScopeCatalogWrite))
+	mux.HandleFunc("DELETE /attachments/{id}", Curator(attachmentHandler.DeleteAttachment, security.ScopeCatalogWrite))
+
+	// admin routes
+	mux.HandleFunc("GET /admin/database/stats", Admin(db.StatsHandler))
+	mux.HandleFunc("PUT /admin/users/{id}/role", Admin(userHandler.ChangeRoleHandler))
+	mux.HandleFunc("GET /admin/companies", Admin(companyHandler.ListCompaniesHandler))
+	mux.HandleFunc("POST /admin/companies", Admin(companyHandler.CreateCompanyHandler))
+	mux.HandleFunc("POST /admin/companies/{id}/users", Admin(companyHandler.AddMemberHandler))
+	mux.HandleFunc("DELETE /admin/companies/{id}/users/{user_id}", Admin(companyHandler.RemoveMemberHandler))
+	mux.HandleFunc("GET /admin/sync-runs", Admin(syncRunHandler.ListSyncRunsHandler))
+	mux.HandleFunc("POST /admin/providers/{name}/sync", Admin(syncRunHandler.TriggerSyncHandler, security.ScopeSyncRun))
+	mux.HandleFunc("GET /admin/category-mappings", Curator(categoryMappingHandler.ListCategoryMappingsHandler))
+	mux.HandleFunc("POST /admin/category-mappings", Admin(categoryMappingHandler.CreateCategoryMappingHandler))
+	mux.HandleFunc("GET /admin/category-mappings/{id}", Curator(categoryMappingHandler.GetCategoryMappingHandler))
+	mux.HandleFunc("PUT /admin/category-mappings/{id}", Admin(categoryMappingHandler.UpdateCategoryMappingHandler))
+	mux.HandleFunc("DELETE /admin/category-mappings/{id}", Admin(categoryMappingHandler.DeleteCategoryMappingHandler))
 
 	corsConfig := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
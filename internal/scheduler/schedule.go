@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule tells when a provider sync should run next
+type Schedule interface {
+	Next(from time.Time) time.Time
+}
+
+// every runs at a fixed interval
+type every struct {
+	interval time.Duration
+}
+
+func (e every) Next(from time.Time) time.Time {
+	return from.Add(e.interval)
+}
+
+// daily runs once a day at the given hour and minute
+type daily struct {
+	hour   int
+	minute int
+}
+
+func (d daily) Next(from time.Time) time.Time {
+	next := time.Date(from.Year(), from.Month(), from.Day(), d.hour, d.minute, 0, 0, from.Location())
+	if !next.After(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// ParseSchedule parses a cron-like schedule. Supported specs are
+// "@every <duration>", "@hourly", "@daily", "@weekly", "@at HH:MM" and a plain
+// duration such as "6h". "off" or an empty spec disables the schedule.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	switch spec {
+	case "", "off":
+		return nil, nil
+	case "@hourly":
+		return every{interval: time.Hour}, nil
+	case "@daily":
+		return daily{}, nil
+	case "@weekly":
+		return every{interval: 7 * 24 * time.Hour}, nil
+	}
+
+	if strings.HasPrefix(spec, "@at ") {
+		at, err := time.Parse("15:04", strings.TrimSpace(strings.TrimPrefix(spec, "@at ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		return daily{hour: at.Hour(), minute: at.Minute()}, nil
+	}
+
+	interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	if interval < time.Minute {
+		return nil, fmt.Errorf("invalid schedule %q: interval must be at least one minute", spec)
+	}
+
+	return every{interval: interval}, nil
+}
+
+// ParseProviderSchedules parses per provider overrides like
+// "muffato=@every 2h,other=@at 03:00"
+func ParseProviderSchedules(specs string) (map[string]string, error) {
+	schedules := map[string]string{}
+	for _, entry := range strings.Split(specs, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid provider schedule %q", entry)
+		}
+		schedules[strings.TrimSpace(name)] = strings.TrimSpace(spec)
+	}
+	return schedules, nil
+}
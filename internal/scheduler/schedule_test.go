@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2025, 11, 2, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+		disabled bool
+		wantErr  bool
+	}{
+		{name: "Every", spec: "@every 2h", expected: from.Add(2 * time.Hour)},
+		{name: "Plain duration", spec: "30m", expected: from.Add(30 * time.Minute)},
+		{name: "Hourly", spec: "@hourly", expected: from.Add(time.Hour)},
+		{name: "Daily", spec: "@daily", expected: time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "At later today", spec: "@at 23:15", expected: time.Date(2025, 11, 2, 23, 15, 0, 0, time.UTC)},
+		{name: "At tomorrow", spec: "@at 03:00", expected: time.Date(2025, 11, 3, 3, 0, 0, 0, time.UTC)},
+		{name: "Off", spec: "off", disabled: true},
+		{name: "Empty", spec: "", disabled: true},
+		{name: "Too short", spec: "@every 10s", wantErr: true},
+		{name: "Invalid", spec: "every day", wantErr: true},
+		{name: "Invalid time", spec: "@at 25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.disabled {
+				if schedule != nil {
+					t.Errorf("Expected disabled schedule, got %v", schedule)
+				}
+				return
+			}
+
+			if next := schedule.Next(from); !next.Equal(tt.expected) {
+				t.Errorf("Next() = %v, want %v", next, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseProviderSchedules(t *testing.T) {
+	schedules, err := ParseProviderSchedules("muffato=@every 2h, other = @at 03:00,")
+	if err != nil {
+		t.Fatalf("ParseProviderSchedules() returned error: %v", err)
+	}
+
+	if schedules["muffato"] != "@every 2h" {
+		t.Errorf("muffato = %q, want @every 2h", schedules["muffato"])
+	}
+	if schedules["other"] != "@at 03:00" {
+		t.Errorf("other = %q, want @at 03:00", schedules["other"])
+	}
+
+	if _, err := ParseProviderSchedules("muffato"); err == nil {
+		t.Error("Expected error for entry without schedule")
+	}
+}
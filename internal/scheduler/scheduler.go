@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"context"
+	"market/internal/domain/sync_run"
+	"market/internal/ingestion"
+	"market/pkg/config"
+	"market/pkg/database"
+	"market/pkg/providers"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Scheduler runs the registered providers in background on their schedules,
+// tracking every run and never running the same provider twice at once, not
+// even across processes: every run holds ingestion.LockProvider.
+type Scheduler struct {
+	log       *zap.SugaredLogger
+	db        *database.PostgresDB
+	registry  *providers.Registry
+	runner    *ingestion.Runner
+	runs      sync_run.UseCase
+	schedules map[string]Schedule
+
+	mu      sync.Mutex
+	running map[string]func()
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ sync_run.Trigger = (*Scheduler)(nil)
+
+// New creates a scheduler using SYNC_SCHEDULE as the default schedule and
+// SYNC_PROVIDER_SCHEDULES as per provider overrides
+func New(
+	log *zap.SugaredLogger,
+	registry *providers.Registry,
+	runner *ingestion.Runner,
+	runs sync_run.UseCase,
+) (*Scheduler, error) {
+	overrides, err := ParseProviderSchedules(config.Get().SYNC_PROVIDER_SCHEDULES)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := map[string]Schedule{}
+	for _, provider := range registry.All() {
+		spec, ok := overrides[provider.Name()]
+		if !ok {
+			spec = config.Get().SYNC_SCHEDULE
+		}
+
+		schedule, err := ParseSchedule(spec)
+		if err != nil {
+			return nil, err
+		}
+		if schedule != nil {
+			schedules[provider.Name()] = schedule
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		log:       log,
+		db:        database.GetInstance(log),
+		registry:  registry,
+		runner:    runner,
+		runs:      runs,
+		schedules: schedules,
+		running:   map[string]func(){},
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+// Start launches one loop per scheduled provider
+func (s *Scheduler) Start() {
+	for name, schedule := range s.schedules {
+		s.wg.Add(1)
+		go s.loop(name, schedule)
+	}
+	s.log.Infow("scheduler started", "providers", len(s.schedules))
+}
+
+// Stop cancels running syncs and waits for them to finish or ctx to expire.
+// Syncs are no longer started once it is called.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Trigger starts a manual sync of the provider in background
+func (s *Scheduler) Trigger(name string) (*sync_run.SyncRun, error) {
+	provider, ok := s.registry.Get(name)
+	if !ok {
+		return nil, sync_run.ErrProviderNotFound
+	}
+
+	run, err := s.begin(provider, sync_run.SyncRunTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.execute(s.ctx, provider, run)
+
+	return run, nil
+}
+
+func (s *Scheduler) loop(name string, schedule Schedule) {
+	defer s.wg.Done()
+
+	next := time.Now()
+	if !config.Get().SYNC_RUN_ON_START {
+		next = schedule.Next(next)
+	}
+
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		provider, ok := s.registry.Get(name)
+		if ok {
+			run, err := s.begin(provider, sync_run.SyncRunTriggerSchedule)
+			if err == nil {
+				s.execute(s.ctx, provider, run)
+			} else {
+				s.log.Warnw("scheduled sync skipped", "provider", name, "error", err)
+			}
+		}
+
+		next = schedule.Next(time.Now())
+	}
+}
+
+// begin locks the provider and records the run start. The run counts in wg
+// until release, so Stop waits for it.
+func (s *Scheduler) begin(provider providers.Provider, trigger sync_run.SyncRunTrigger) (*sync_run.SyncRun, error) {
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return nil, sync_run.ErrSchedulerStopped
+	}
+	if _, running := s.running[provider.Name()]; running {
+		s.mu.Unlock()
+		return nil, sync_run.ErrAlreadyRunning
+	}
+	s.running[provider.Name()] = func() {}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	unlock, err := ingestion.LockProvider(s.ctx, s.db, provider.Name())
+	if err != nil {
+		s.release(provider.Name())
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.running[provider.Name()] = unlock
+	s.mu.Unlock()
+
+	run, err := s.runs.Start(s.ctx, provider.Name(), trigger)
+	if err != nil {
+		s.release(provider.Name())
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// execute runs the ingestion and records its outcome
+func (s *Scheduler) execute(ctx context.Context, provider providers.Provider, run *sync_run.SyncRun) {
+	defer s.release(provider.Name())
+
+	report, err := s.runner.Run(ctx, provider)
+	if report != nil {
+		run.PagesFetched = report.Pages
+		run.ProductsFetched = report.Fetched
+		run.ProductsUpserted = report.Saved
+		run.Errors = report.Failed
+		run.UnmappedCategories = report.Unmapped
+	}
+	run.Finish(err)
+
+	if err != nil {
+		s.log.Errorw("provider sync failed", "provider", provider.Name(), "run_id", run.ID, "error", err)
+	}
+
+	// Still record runs interrupted by Stop
+	if err := s.runs.Finish(context.WithoutCancel(ctx), run); err != nil {
+		s.log.Errorw("error recording sync run", "provider", provider.Name(), "run_id", run.ID, "error", err)
+	}
+}
+
+func (s *Scheduler) release(name string) {
+	s.mu.Lock()
+	unlock := s.running[name]
+	delete(s.running, name)
+	s.mu.Unlock()
+
+	unlock()
+	s.wg.Done()
+}
@@ -0,0 +1,136 @@
+package apperr
+
+import "errors"
+
+// Kind groups errors by how the caller should react to them, the HTTP layer
+// maps each kind to a status code
+type Kind string
+
+const (
+	KindInternal        Kind = "internal"
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindValidation      Kind = "validation"
+	KindUnauthorized    Kind = "unauthorized"
+	KindForbidden       Kind = "forbidden"
+	KindTooManyRequests Kind = "too_many_requests"
+	KindTooLarge        Kind = "too_large"
+	KindUpstream        Kind = "upstream"
+)
+
+// ErrValidation is returned with the fields that failed validation
+var ErrValidation = Validation("validation_failed", "one or more fields are invalid")
+
+// FieldError points a validation failure to the input field that caused it
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is the error returned by services. Code is a stable machine readable
+// identifier, Message is safe to show to clients and Err keeps the cause for
+// logs only.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, so a sentinel still matches after
+// being wrapped or given field details
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// Wrap returns a copy of the error keeping err as the cause
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithFields returns a copy of the error with the given field details
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &c
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func TooManyRequests(code, message string) *Error {
+	return New(KindTooManyRequests, code, message)
+}
+
+func Upstream(code, message string) *Error {
+	return New(KindUpstream, code, message)
+}
+
+// Field builds a FieldError
+func Field(field, message string) FieldError {
+	return FieldError{Field: field, Message: message}
+}
+
+// Invalid returns ErrValidation listing fields, nil when there are none
+func Invalid(fields ...FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return ErrValidation.WithFields(fields...)
+}
+
+// As returns the first *Error in the chain of err
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of err, errors that are not *Error are internal
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
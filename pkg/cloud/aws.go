@@ -2,6 +2,7 @@ package cloud
 
 import (
 	"bytes"
+	"context"
 	"market/pkg/config"
 	"fmt"
 	"io"
@@ -112,6 +113,22 @@ func downloadImage(url string) (*bytes.Buffer, error) {
 	return &buf, nil
 }
 
+// CheckBucket confirma que o bucket existe e que as credenciais têm acesso a ele
+func (a *AWS) CheckBucket(ctx context.Context, bucket string) error {
+	if a.Sessions == nil {
+		return fmt.Errorf("aws session not initialized")
+	}
+
+	s3Client := s3.New(a.Sessions)
+	_, err := s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach bucket %s: %v", bucket, err)
+	}
+	return nil
+}
+
 func (a *AWS) GetSession() interface{} {
 	return a.Sessions
 }
@@ -1,6 +1,9 @@
 package cloud
 
-import "sync"
+import (
+	"context"
+	"sync"
+)
 
 type Provider string
 
@@ -14,6 +17,7 @@ type CloudProvider interface {
 	Bootstrap()
 	UploadImageFromURL(url string, bucket string, imageID string) error
 	UploadFile(fileContent []byte, bucket string, contentType string) (string, error)
+	CheckBucket(ctx context.Context, bucket string) error
 }
 
 type Cloud struct {
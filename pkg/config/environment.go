@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,8 +16,17 @@ var (
 )
 
 type Env struct {
-	ENV               string
-	SERVER_PORT       string
+	ENV         string
+	SERVER_PORT string
+
+	SERVER_READ_TIMEOUT        time.Duration
+	SERVER_READ_HEADER_TIMEOUT time.Duration
+	SERVER_WRITE_TIMEOUT       time.Duration
+	SERVER_IDLE_TIMEOUT        time.Duration
+	SERVER_SHUTDOWN_TIMEOUT    time.Duration
+	// SERVER_MAX_BODY_BYTES caps the JSON request bodies
+	SERVER_MAX_BODY_BYTES int
+
 	DATABASE_DRIVER   string
 	DATABASE_HOST     string
 	DATABASE_USER     string
@@ -24,6 +35,16 @@ type Env struct {
 	DATABASE_PORT     string
 	DATABASE_SCHEMA   string
 
+	DATABASE_SSLMODE            string
+	DATABASE_SSLROOTCERT        string
+	DATABASE_MAX_OPEN_CONNS     int
+	DATABASE_MAX_IDLE_CONNS     int
+	DATABASE_CONN_MAX_LIFETIME  time.Duration
+	DATABASE_CONN_MAX_IDLE_TIME time.Duration
+	DATABASE_STATEMENT_TIMEOUT  time.Duration
+	DATABASE_CONNECT_RETRIES    int
+	DATABASE_CONNECT_BACKOFF    time.Duration
+
 	CLOUD_ENV          string
 	CLOUD_KEY          string
 	CLOUD_SECRET       string
@@ -32,6 +53,49 @@ type Env struct {
 	CLOUD_HOST         string
 	CLOUD_BUCKET       string
 	CLOUD_HOST_BUCKET  string
+
+	MUFFATO_MARKET_ID string
+	MUFFATO_BASE_URL  string
+	MUFFATO_PAGE_SIZE int
+	VTEX_STORES_FILE  string
+
+	SYNC_SCHEDULE           string
+	SYNC_PROVIDER_SCHEDULES string
+	SYNC_RUN_ON_START       bool
+	SYNC_SCHEDULER_ENABLED  bool
+
+	JWT_KEYS        string
+	JWT_ACTIVE_KID  string
+	JWT_ISSUER      string
+	JWT_ACCESS_TTL  time.Duration
+	JWT_REFRESH_TTL time.Duration
+
+	API_BASE_URL           string
+	APP_BASE_URL           string
+	EMAIL_VERIFICATION_TTL time.Duration
+	PASSWORD_RESET_TTL     time.Duration
+	MAILER_DRIVER          string
+	MAILER_FROM            string
+	MAILER_DIR             string
+	SMTP_HOST              string
+	SMTP_PORT              string
+	SMTP_USER              string
+	SMTP_PASSWORD          string
+
+	LOGIN_FREE_ATTEMPTS   int
+	LOGIN_BASE_DELAY      time.Duration
+	LOGIN_LOCK_AFTER      int
+	LOGIN_LOCK_DURATION   time.Duration
+	LOGIN_IP_MAX_FAILURES int
+	LOGIN_IP_WINDOW       time.Duration
+	// TRUST_PROXY_HEADERS reads the client IP from X-Forwarded-For, only
+	// safe when TRUSTED_PROXY_HOPS proxies all append to it in front of the
+	// API. The default of 0 hops keeps using the connection address.
+	TRUST_PROXY_HEADERS bool
+	TRUSTED_PROXY_HOPS  int
+
+	HEALTH_CHECK_TIMEOUT time.Duration
+	HEALTH_SYNC_MAX_AGE  time.Duration
 }
 
 func Load() {
@@ -42,8 +106,16 @@ func Load() {
 		}
 
 		instance = &Env{
-			ENV:               getEnv("ENV", "development"),
-			SERVER_PORT:       getEnv("SERVER_PORT", ""),
+			ENV:         getEnv("ENV", "development"),
+			SERVER_PORT: getEnv("SERVER_PORT", ""),
+
+			SERVER_READ_TIMEOUT:        getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			SERVER_READ_HEADER_TIMEOUT: getEnvAsDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			SERVER_WRITE_TIMEOUT:       getEnvAsDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			SERVER_IDLE_TIMEOUT:        getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			SERVER_SHUTDOWN_TIMEOUT:    getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			SERVER_MAX_BODY_BYTES:      getEnvAsInt("SERVER_MAX_BODY_BYTES", 1<<20),
+
 			DATABASE_HOST:     getEnv("DATABASE_HOST", ""),
 			DATABASE_USER:     getEnv("DATABASE_USER", ""),
 			DATABASE_NAME:     getEnv("DATABASE_NAME", ""),
@@ -51,6 +123,16 @@ func Load() {
 			DATABASE_PORT:     getEnv("DATABASE_PORT", ""),
 			DATABASE_SCHEMA:   getEnv("DATABASE_SCHEMA", ""),
 
+			DATABASE_SSLMODE:            getEnv("DATABASE_SSLMODE", "disable"),
+			DATABASE_SSLROOTCERT:        getEnv("DATABASE_SSLROOTCERT", ""),
+			DATABASE_MAX_OPEN_CONNS:     getEnvAsInt("DATABASE_MAX_OPEN_CONNS", 25),
+			DATABASE_MAX_IDLE_CONNS:     getEnvAsInt("DATABASE_MAX_IDLE_CONNS", 5),
+			DATABASE_CONN_MAX_LIFETIME:  getEnvAsDuration("DATABASE_CONN_MAX_LIFETIME", 30*time.Minute),
+			DATABASE_CONN_MAX_IDLE_TIME: getEnvAsDuration("DATABASE_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			DATABASE_STATEMENT_TIMEOUT:  getEnvAsDuration("DATABASE_STATEMENT_TIMEOUT", 30*time.Second),
+			DATABASE_CONNECT_RETRIES:    getEnvAsInt("DATABASE_CONNECT_RETRIES", 5),
+			DATABASE_CONNECT_BACKOFF:    getEnvAsDuration("DATABASE_CONNECT_BACKOFF", time.Second),
+
 			CLOUD_ENV:          getEnv("CLOUD_ENV", "aws"),
 			CLOUD_KEY:          getEnv("CLOUD_KEY", ""),
 			CLOUD_SECRET:       getEnv("CLOUD_SECRET", ""),
@@ -59,6 +141,46 @@ func Load() {
 			CLOUD_HOST:         getEnv("CLOUD_HOST", "https://s3.sa-east-1.amazonaws.com"),
 			CLOUD_BUCKET:       getEnv("CLOUD_BUCKET", "market-prd"),
 			CLOUD_HOST_BUCKET:  getEnv("CLOUD_HOST_BUCKET", "https://market-prd.s3.sa-east-1.amazonaws.com"),
+
+			MUFFATO_MARKET_ID: getEnv("MUFFATO_MARKET_ID", "65dcfe06-0381-47fa-8fee-64aa45fa30b4"),
+			MUFFATO_BASE_URL:  getEnv("MUFFATO_BASE_URL", "https://www.supermuffato.com.br"),
+			MUFFATO_PAGE_SIZE: getEnvAsInt("MUFFATO_PAGE_SIZE", 50),
+			VTEX_STORES_FILE:  getEnv("VTEX_STORES_FILE", ""),
+
+			SYNC_SCHEDULE:           getEnv("SYNC_SCHEDULE", "@every 6h"),
+			SYNC_PROVIDER_SCHEDULES: getEnv("SYNC_PROVIDER_SCHEDULES", ""),
+			SYNC_RUN_ON_START:       getEnvAsBool("SYNC_RUN_ON_START", true),
+			SYNC_SCHEDULER_ENABLED:  getEnvAsBool("SYNC_SCHEDULER_ENABLED", false),
+
+			JWT_KEYS:        getEnv("JWT_KEYS", ""),
+			JWT_ACTIVE_KID:  getEnv("JWT_ACTIVE_KID", ""),
+			JWT_ISSUER:      getEnv("JWT_ISSUER", "market"),
+			JWT_ACCESS_TTL:  getEnvAsDuration("JWT_ACCESS_TTL", 15*time.Minute),
+			JWT_REFRESH_TTL: getEnvAsDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+
+			API_BASE_URL:           getEnv("API_BASE_URL", "http://localhost:8080"),
+			APP_BASE_URL:           getEnv("APP_BASE_URL", "http://localhost:3000"),
+			EMAIL_VERIFICATION_TTL: getEnvAsDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+			PASSWORD_RESET_TTL:     getEnvAsDuration("PASSWORD_RESET_TTL", time.Hour),
+			MAILER_DRIVER:          getEnv("MAILER_DRIVER", "log"),
+			MAILER_FROM:            getEnv("MAILER_FROM", "Market <no-reply@market.local>"),
+			MAILER_DIR:             getEnv("MAILER_DIR", ""),
+			SMTP_HOST:              getEnv("SMTP_HOST", ""),
+			SMTP_PORT:              getEnv("SMTP_PORT", "587"),
+			SMTP_USER:              getEnv("SMTP_USER", ""),
+			SMTP_PASSWORD:          getEnv("SMTP_PASSWORD", ""),
+
+			LOGIN_FREE_ATTEMPTS:   getEnvAsInt("LOGIN_FREE_ATTEMPTS", 3),
+			LOGIN_BASE_DELAY:      getEnvAsDuration("LOGIN_BASE_DELAY", 5*time.Second),
+			LOGIN_LOCK_AFTER:      getEnvAsInt("LOGIN_LOCK_AFTER", 10),
+			LOGIN_LOCK_DURATION:   getEnvAsDuration("LOGIN_LOCK_DURATION", 30*time.Minute),
+			LOGIN_IP_MAX_FAILURES: getEnvAsInt("LOGIN_IP_MAX_FAILURES", 50),
+			LOGIN_IP_WINDOW:       getEnvAsDuration("LOGIN_IP_WINDOW", 15*time.Minute),
+			TRUST_PROXY_HEADERS:   getEnvAsBool("TRUST_PROXY_HEADERS", false),
+			TRUSTED_PROXY_HOPS:    getEnvAsInt("TRUSTED_PROXY_HOPS", 0),
+
+			HEALTH_CHECK_TIMEOUT: getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			HEALTH_SYNC_MAX_AGE:  getEnvAsDuration("HEALTH_SYNC_MAX_AGE", 24*time.Hour),
 		}
 	})
 
@@ -84,6 +206,24 @@ func getEnvAsBool(key string, fallback bool) bool {
 	return fallback
 }
 
+func getEnvAsInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
 func Get() *Env {
 	if instance == nil {
 		panic("Envuration not loaded. Call config.Load() first.")
@@ -1,10 +1,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-	"market/pkg/config"
 	"fmt"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
@@ -23,29 +24,53 @@ var (
 // GetInstance retorna a instância singleton do banco de dados
 func GetInstance(log *zap.SugaredLogger) *PostgresDB {
 	once.Do(func() {
-		connStr := fmt.Sprintf(
-			"host=%s user=%s dbname=%s password=%s sslmode=disable",
-			config.Get().DATABASE_HOST,
-			config.Get().DATABASE_USER,
-			config.Get().DATABASE_NAME,
-			config.Get().DATABASE_PASSWORD,
-		)
-
-		db, err := sql.Open("postgres", connStr)
+		db, err := Open(context.Background(), OptionsFromConfig(), log)
 		if err != nil {
 			log.Fatalf("❌ Falha ao conectar no banco: %v", err)
 		}
 
-		if err = db.Ping(); err != nil {
-			log.Fatalf("❌ Falha ao pingar o banco: %v", err)
-		}
-
 		log.Info("✅ Conectado com sucesso ao PostgreSQL")
-		instance = &PostgresDB{conn: db}
+		instance = db
 	})
 	return instance
 }
 
+// Open abre o pool e tenta o ping até ConnectRetries vezes, dobrando a espera
+// a cada tentativa, para o serviço sobreviver ao banco subindo junto com ele
+func Open(ctx context.Context, opts Options, log *zap.SugaredLogger) (*PostgresDB, error) {
+	conn, err := sql.Open("postgres", opts.DSN())
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetMaxOpenConns(opts.MaxOpenConns)
+	conn.SetMaxIdleConns(opts.MaxIdleConns)
+	conn.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	for attempt := 0; ; attempt++ {
+		err = conn.PingContext(ctx)
+		if err == nil {
+			return &PostgresDB{conn: conn}, nil
+		}
+		if attempt >= opts.ConnectRetries {
+			break
+		}
+
+		delay := retryDelay(opts.ConnectBackoff, attempt)
+		log.Warnw("database not ready, retrying", "attempt", attempt+1, "retry_in", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ctx.Err()
+		}
+	}
+
+	conn.Close()
+	return nil, fmt.Errorf("ping after %d attempts: %w", opts.ConnectRetries+1, err)
+}
+
 // Close fecha a conexão com o banco
 func (db *PostgresDB) Close() error {
 	return db.conn.Close()
@@ -61,6 +86,11 @@ func (db *PostgresDB) Exec(query string, args ...interface{}) (sql.Result, error
 	return db.conn.Exec(query, args...)
 }
 
+// Ping verifica se o banco responde
+func (db *PostgresDB) Ping(ctx context.Context) error {
+	return db.conn.PingContext(ctx)
+}
+
 // Prepare prepara uma instrução para execução posterior
 func (db *PostgresDB) Prepare(query string) (*sql.Stmt, error) {
 	return db.conn.Prepare(query)
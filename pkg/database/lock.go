@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+)
+
+// ErrLocked is returned by TryLock when another session holds the lock
+var ErrLocked = errors.New("lock held by another session")
+
+// TryLock takes the advisory lock named key without waiting, on a connection
+// kept out of the pool until release is called. Being a database lock it also
+// excludes other processes and replicas, and Postgres drops it if the process
+// dies holding it.
+func (db *PostgresDB) TryLock(ctx context.Context, key string) (release func(), err error) {
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !locked {
+		conn.Close()
+		return nil, ErrLocked
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			// Discard the connection so the session, and the lock, end with it
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
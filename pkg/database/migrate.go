@@ -0,0 +1,399 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+// migrationLockID is the pg_advisory_lock key that serializes concurrent migrators
+const migrationLockID int64 = 7_356_114_201
+
+// MigrationsDir is where `migrate create` writes new migration files
+const MigrationsDir = "pkg/database/migrations"
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrIrreversible     = errors.New("migration has no down script")
+	ErrUnknownMigration = errors.New("database has a migration that is not in this build")
+)
+
+// Migration is an ordered pair of up/down SQL scripts
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus describes a migration and whether it is applied in the database
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified"`
+	Missing   bool       `json:"missing"`
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations and tracks them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	log        *zap.SugaredLogger
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator with the migrations embedded in the binary
+func NewMigrator(db *PostgresDB, log *zap.SugaredLogger) (*Migrator, error) {
+	sub, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db.conn,
+		log:        log,
+		migrations: migrations,
+	}, nil
+}
+
+// LoadMigrations reads <version>_<name>.up.sql / .down.sql pairs sorted by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migration.Checksum = checksum(migration.Up)
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration, each one in its own transaction
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			m.log.Infow("applying migration", "version", migration.Version, "name", migration.Name)
+			err := runInTx(ctx, conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`,
+				migration.Version, migration.Name, migration.Checksum,
+			)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversible, migration.Version, migration.Name)
+			}
+
+			m.log.Infow("reverting migration", "version", migration.Version, "name", migration.Name)
+			err := runInTx(ctx, conn, migration.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`,
+				migration.Version,
+			)
+			if err != nil {
+				return fmt.Errorf("revert of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every known migration plus applied versions missing from this build
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []MigrationStatus{}
+	known := map[int64]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = row.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, row := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      row.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// CreateMigration writes an empty up/down pair in dir using the current time as version
+func CreateMigration(dir, name string, now time.Time) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", errors.New("migration name is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+
+	version := now.UTC().Format("200601021504")
+	up := filepath.Join(dir, fmt.Sprintf("%s_%s.up.sql", version, name))
+	down := filepath.Join(dir, fmt.Sprintf("%s_%s.down.sql", version, name))
+
+	for _, path := range []string{up, down} {
+		if _, err := os.Stat(path); err == nil {
+			return "", "", fmt.Errorf("migration %s already exists", path)
+		}
+	}
+
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- revert "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+
+	return up, down, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock,
+// so two instances starting at the same time do not apply the same migration
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.log.Errorw("error releasing migration lock", "error", err)
+		}
+	}()
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var row appliedMigration
+		if err := rows.Scan(&row.Version, &row.Name, &row.Checksum, &row.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[row.Version] = row
+	}
+
+	return applied, rows.Err()
+}
+
+// verify refuses to run when an applied migration was edited or is unknown to this build
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	known := map[int64]Migration{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, row := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: %d_%s", ErrUnknownMigration, version, row.Name)
+		}
+		if migration.Checksum != row.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func runInTx(ctx context.Context, conn *sql.Conn, script, track string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, track, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"202601010000_second.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"202601010000_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"202501010000_first.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Name != "first" || migrations[1].Name != "second" {
+		t.Errorf("migrations not sorted by version: %+v", migrations)
+	}
+	if migrations[1].Down != "DROP TABLE b;" {
+		t.Errorf("down script not loaded: %q", migrations[1].Down)
+	}
+	if migrations[0].Checksum == "" || migrations[0].Checksum == migrations[1].Checksum {
+		t.Errorf("unexpected checksums %q and %q", migrations[0].Checksum, migrations[1].Checksum)
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"invalid name": {
+			"create_users.sql": {Data: []byte("SELECT 1;")},
+		},
+		"missing up": {
+			"202501010000_first.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"duplicate version": {
+			"202501010000_first.up.sql":  {Data: []byte("SELECT 1;")},
+			"202501010000_second.up.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range cases {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	sub, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		t.Fatalf("embedded migrations are invalid: %v", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.Down == "" {
+			t.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 17, 15, 4, 0, 0, time.UTC)
+
+	up, down, err := CreateMigration(dir, "Add API Keys", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if filepath.Base(up) != "202610171504_add_api_keys.up.sql" {
+		t.Errorf("unexpected up file %s", up)
+	}
+	if filepath.Base(down) != "202610171504_add_api_keys.down.sql" {
+		t.Errorf("unexpected down file %s", down)
+	}
+	if _, err := os.Stat(up); err != nil {
+		t.Errorf("up file not written: %v", err)
+	}
+
+	if _, _, err := CreateMigration(dir, "add api keys", now); err == nil {
+		t.Errorf("expected error when the migration already exists")
+	}
+}
+
+func TestEmbeddedSeeds(t *testing.T) {
+	seeds, err := EmbeddedSeeds()
+	if err != nil {
+		t.Fatalf("embedded seeds are invalid: %v", err)
+	}
+
+	position := map[string]int{}
+	for i, seed := range seeds {
+		position[seed.Name] = i
+	}
+
+	for _, name := range []string{"categories", "markets", "provider_category_mappings"} {
+		if _, ok := position[name]; !ok {
+			t.Errorf("seed %s not found", name)
+		}
+	}
+	if position["provider_category_mappings"] < position["categories"] {
+		t.Errorf("mappings must be seeded after categories")
+	}
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS product_markets;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS market_locations;
+DROP TABLE IF EXISTS markets;
+DROP TABLE IF EXISTS cities;
+DROP TABLE IF EXISTS states;
@@ -100,6 +100,8 @@ CREATE INDEX idx_users_email ON users(email);
 CREATE TABLE categories (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    company_id UUID,
     status VARCHAR(20) DEFAULT 'active', -- active, inactive, deleted
     category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
@@ -134,10 +136,3 @@ CREATE TABLE product_markets (
 CREATE INDEX idx_product_markets_product_id ON product_markets(product_id);
 CREATE INDEX idx_product_markets_market_id ON product_markets(market_id);
 CREATE INDEX idx_product_markets_provider_id ON product_markets(provider_id);
-
-INSERT INTO public.markets
-(id, "name", description, status, created_at, updated_at)
-VALUES('65dcfe06-0381-47fa-8fee-64aa45fa30b4'::uuid, 'Muffato', 'Muffato', 'active', '2025-11-02 22:18:54.834', '2025-11-02 22:18:54.834');
-INSERT INTO public.markets
-(id, "name", description, status, created_at, updated_at)
-VALUES('f7c82abd-bd7b-4bf6-a0fc-811e2d589b89'::uuid, 'Amigão', 'Amigão', 'active', '2025-11-02 22:18:54.834', '2025-11-02 22:18:54.834');
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE price_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    market_id UUID NOT NULL,
+    price NUMERIC(10,2) NOT NULL,
+    promotional_price NUMERIC(10,2),
+    source VARCHAR(50) NOT NULL, -- manual or provider name
+    observed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (market_id) REFERENCES markets(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_price_history_product_observed_at ON price_history(product_id, observed_at);
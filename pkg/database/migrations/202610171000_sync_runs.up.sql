@@ -0,0 +1,15 @@
+CREATE TABLE sync_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider VARCHAR(50) NOT NULL,
+    triggered_by VARCHAR(20) NOT NULL, -- schedule, manual
+    status VARCHAR(20) NOT NULL, -- running, success, failed
+    pages_fetched INTEGER NOT NULL DEFAULT 0,
+    products_fetched INTEGER NOT NULL DEFAULT 0,
+    products_upserted INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    unmapped_categories TEXT[] NOT NULL DEFAULT '{}',
+    error_message TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_sync_runs_provider_started_at ON sync_runs(provider, started_at DESC);
@@ -0,0 +1,10 @@
+CREATE TABLE provider_category_mappings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider VARCHAR(50) NOT NULL,
+    external_id VARCHAR(255) NOT NULL, -- category ID ("8") or path ("/Carnes, Aves e Peixes/Frango/")
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL, -- NULL while unmapped
+    last_seen_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(provider, external_id)
+);
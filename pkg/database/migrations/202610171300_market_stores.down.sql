@@ -0,0 +1,2 @@
+ALTER TABLE product_markets DROP COLUMN IF EXISTS store_id;
+DROP TABLE IF EXISTS market_stores;
@@ -0,0 +1,23 @@
+CREATE TABLE market_stores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    market_id UUID NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    number VARCHAR(20),
+    district VARCHAR(100),
+    city VARCHAR(100) NOT NULL,
+    state_id VARCHAR(2) NOT NULL,
+    cep VARCHAR(8) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    opening_hours JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) DEFAULT 'active', -- active, inactive, deleted
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (market_id) REFERENCES markets(id) ON DELETE CASCADE,
+    FOREIGN KEY (state_id) REFERENCES states(id)
+);
+CREATE INDEX idx_market_stores_market ON market_stores(market_id);
+CREATE INDEX idx_market_stores_location ON market_stores(latitude, longitude);
+
+ALTER TABLE product_markets ADD COLUMN store_id UUID REFERENCES market_stores(id) ON DELETE SET NULL;
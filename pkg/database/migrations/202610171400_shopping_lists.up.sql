@@ -0,0 +1,40 @@
+CREATE TABLE shopping_lists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) DEFAULT 'active', -- active, deleted
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_shopping_lists_owner_id ON shopping_lists(owner_id);
+
+CREATE TABLE shopping_list_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    list_id UUID NOT NULL,
+    product_id UUID,
+    description VARCHAR(255),
+    quantity NUMERIC(10,3) NOT NULL DEFAULT 1,
+    unit VARCHAR(20),
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    checked_by UUID,
+    checked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (list_id) REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL,
+    FOREIGN KEY (checked_by) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (product_id IS NOT NULL OR description IS NOT NULL)
+);
+CREATE INDEX idx_shopping_list_items_list_id ON shopping_list_items(list_id);
+
+CREATE TABLE shopping_list_members (
+    list_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    permission VARCHAR(20) NOT NULL, -- viewer, editor
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (list_id, user_id),
+    FOREIGN KEY (list_id) REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_shopping_list_members_user_id ON shopping_list_members(user_id);
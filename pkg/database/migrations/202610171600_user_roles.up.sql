@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member'; -- admin, curator, member
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'curator', 'member'));
@@ -0,0 +1,16 @@
+CREATE TABLE user_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    purpose VARCHAR(30) NOT NULL, -- email_verification, password_reset
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens(user_id, purpose);
+
+-- Replaced by user_tokens, they were never written
+ALTER TABLE users DROP COLUMN IF EXISTS email_verification_token;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_token;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_expires;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN failed_logins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID, -- null when the email is not registered
+    email VARCHAR(80) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255),
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_login_attempts_user_id ON login_attempts(user_id, created_at DESC);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip, created_at DESC) WHERE NOT success;
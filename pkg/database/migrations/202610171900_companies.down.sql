@@ -0,0 +1,9 @@
+ALTER TABLE shopping_lists DROP COLUMN IF EXISTS company_id;
+ALTER TABLE attachments DROP COLUMN IF EXISTS company_id;
+ALTER TABLE price_history DROP COLUMN IF EXISTS company_id;
+ALTER TABLE product_markets DROP COLUMN IF EXISTS company_id;
+DROP INDEX IF EXISTS idx_categories_company_id;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_company_id_fkey;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS company_id;
+DROP TABLE IF EXISTS company_users;
+DROP TABLE IF EXISTS companies;
@@ -0,0 +1,45 @@
+CREATE TABLE companies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(120) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, inactive
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing users and data belong to the default company
+INSERT INTO companies (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+CREATE TABLE company_users (
+    company_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (company_id, user_id),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_company_users_user_id ON company_users(user_id);
+
+INSERT INTO company_users (company_id, user_id)
+SELECT '00000000-0000-0000-0000-000000000001', id FROM users;
+
+-- The refresh token keeps the active company across rotations
+ALTER TABLE refresh_tokens ADD COLUMN company_id UUID REFERENCES companies(id) ON DELETE CASCADE;
+
+-- Categories and prices without company are shared by every company
+ALTER TABLE categories ADD CONSTRAINT categories_company_id_fkey
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE;
+CREATE INDEX idx_categories_company_id ON categories(company_id);
+
+ALTER TABLE product_markets ADD COLUMN company_id UUID REFERENCES companies(id) ON DELETE CASCADE;
+CREATE INDEX idx_product_markets_company_id ON product_markets(company_id);
+
+ALTER TABLE price_history ADD COLUMN company_id UUID REFERENCES companies(id) ON DELETE CASCADE;
+
+ALTER TABLE attachments ADD COLUMN company_id UUID REFERENCES companies(id) ON DELETE CASCADE;
+UPDATE attachments SET company_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE attachments ALTER COLUMN company_id SET NOT NULL;
+
+ALTER TABLE shopping_lists ADD COLUMN company_id UUID REFERENCES companies(id) ON DELETE CASCADE;
+UPDATE shopping_lists SET company_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE shopping_lists ALTER COLUMN company_id SET NOT NULL;
+CREATE INDEX idx_shopping_lists_company_id ON shopping_lists(company_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_product_markets_market_provider;
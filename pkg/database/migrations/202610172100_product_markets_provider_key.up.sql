@@ -0,0 +1,17 @@
+-- The sync finds prices by (market_id, provider_id), so retire the duplicates
+-- left by concurrent runs, keeping the latest one, before making it unique
+UPDATE product_markets pm SET status = 'deleted', updated_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY market_id, provider_id, company_id
+        ORDER BY updated_at DESC, created_at DESC, id
+    ) AS position
+    FROM product_markets
+    WHERE provider_id IS NOT NULL AND status != 'deleted'
+) duplicates
+WHERE pm.id = duplicates.id AND duplicates.position > 1;
+
+-- Shared prices have no company, private ones are unique inside their company
+CREATE UNIQUE INDEX idx_product_markets_market_provider ON product_markets(
+    market_id, provider_id, COALESCE(company_id, '00000000-0000-0000-0000-000000000000')
+) WHERE provider_id IS NOT NULL AND status != 'deleted';
@@ -0,0 +1,2 @@
+-- Fields a curator edited by hand, which the sync no longer overwrites
+ALTER TABLE products ADD COLUMN curated_fields TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,18 @@
+-- Listings sort and page by these columns, which needs them never NULL
+UPDATE products SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE products SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE products
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+UPDATE product_markets SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE product_markets SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE product_markets
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+UPDATE attachments SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE attachments SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE attachments
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
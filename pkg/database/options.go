@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+	"market/pkg/config"
+	"strings"
+	"time"
+)
+
+const maxRetryDelay = 30 * time.Second
+
+// Options holds the connection and pool settings of PostgresDB
+type Options struct {
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	Name        string
+	Schema      string
+	SSLMode     string
+	SSLRootCert string
+
+	// StatementTimeout aborts queries running longer than it, zero disables it
+	StatementTimeout time.Duration
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	ConnectRetries int
+	ConnectBackoff time.Duration
+}
+
+// OptionsFromConfig reads the DATABASE_* settings
+func OptionsFromConfig() Options {
+	env := config.Get()
+	return Options{
+		Host:             env.DATABASE_HOST,
+		Port:             env.DATABASE_PORT,
+		User:             env.DATABASE_USER,
+		Password:         env.DATABASE_PASSWORD,
+		Name:             env.DATABASE_NAME,
+		Schema:           env.DATABASE_SCHEMA,
+		SSLMode:          env.DATABASE_SSLMODE,
+		SSLRootCert:      env.DATABASE_SSLROOTCERT,
+		StatementTimeout: env.DATABASE_STATEMENT_TIMEOUT,
+		MaxOpenConns:     env.DATABASE_MAX_OPEN_CONNS,
+		MaxIdleConns:     env.DATABASE_MAX_IDLE_CONNS,
+		ConnMaxLifetime:  env.DATABASE_CONN_MAX_LIFETIME,
+		ConnMaxIdleTime:  env.DATABASE_CONN_MAX_IDLE_TIME,
+		ConnectRetries:   env.DATABASE_CONNECT_RETRIES,
+		ConnectBackoff:   env.DATABASE_CONNECT_BACKOFF,
+	}
+}
+
+// DSN builds the key/value connection string understood by lib/pq. Settings
+// unknown to the driver, like search_path and statement_timeout, are sent to
+// the server as session parameters.
+func (o Options) DSN() string {
+	params := []struct{ key, value string }{
+		{"host", o.Host},
+		{"port", o.Port},
+		{"user", o.User},
+		{"password", o.Password},
+		{"dbname", o.Name},
+		{"sslmode", o.SSLMode},
+		{"sslrootcert", o.SSLRootCert},
+		{"search_path", o.Schema},
+	}
+	if o.StatementTimeout > 0 {
+		params = append(params, struct{ key, value string }{"statement_timeout", fmt.Sprint(o.StatementTimeout.Milliseconds())})
+	}
+
+	parts := []string{}
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		parts = append(parts, param.key+"="+quoteDSNValue(param.value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSNValue quotes values with spaces, quotes or backslashes, as in libpq
+func quoteDSNValue(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// retryDelay doubles the base delay on every attempt up to maxRetryDelay
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOptionsDSN(t *testing.T) {
+	opts := Options{
+		Host:             "db.internal",
+		Port:             "5433",
+		User:             "market",
+		Password:         "p@ss word'\\",
+		Name:             "market",
+		Schema:           "market",
+		SSLMode:          "verify-full",
+		SSLRootCert:      "/etc/ssl/rds.pem",
+		StatementTimeout: 15 * time.Second,
+	}
+
+	want := `host=db.internal port=5433 user=market password='p@ss word\'\\' dbname=market ` +
+		`sslmode=verify-full sslrootcert=/etc/ssl/rds.pem search_path=market statement_timeout=15000`
+	if got := opts.DSN(); got != want {
+		t.Fatalf("DSN() = %s, want %s", got, want)
+	}
+}
+
+func TestOptionsDSNSkipsEmptyValues(t *testing.T) {
+	opts := Options{Host: "localhost", User: "market", Name: "market", SSLMode: "disable"}
+
+	want := "host=localhost user=market dbname=market sslmode=disable"
+	if got := opts.DSN(); got != want {
+		t.Fatalf("DSN() = %s, want %s", got, want)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		base    time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 1, 2 * time.Second},
+		{time.Second, 3, 8 * time.Second},
+		{time.Second, 10, maxRetryDelay},
+		{0, 5, 0},
+	}
+
+	for _, c := range cases {
+		if got := retryDelay(c.base, c.attempt); got != c.want {
+			t.Errorf("retryDelay(%s, %d) = %s, want %s", c.base, c.attempt, got, c.want)
+		}
+	}
+}
@@ -12,4 +12,5 @@ INSERT INTO public.categories (id,"name",status,category_id,created_at,updated_a
 	 ('10c4f7aa-b79e-4e81-b96a-eb20b538e37c'::uuid,'Higiene e Beleza','active',NULL,'2025-11-02 22:06:56.49694-03','2025-11-02 22:06:56.49694-03'),
 	 ('eeb79bb5-881b-49bb-9c5e-f6f9989e9ec9'::uuid,'Hortifruti','active',NULL,'2025-11-02 22:06:56.49694-03','2025-11-02 22:06:56.49694-03'),
 	 ('af852929-24a9-4a54-9b34-baeef6b3ea75'::uuid,'Limpeza','active',NULL,'2025-11-02 22:06:56.49694-03','2025-11-02 22:06:56.49694-03'),
-	 ('a93c8d3a-01e7-4b94-a054-a2b573eaeaf8'::uuid,'Outros','active',NULL,'2025-11-02 22:06:56.49694-03','2025-11-02 22:06:56.49694-03');
+	 ('a93c8d3a-01e7-4b94-a054-a2b573eaeaf8'::uuid,'Outros','active',NULL,'2025-11-02 22:06:56.49694-03','2025-11-02 22:06:56.49694-03')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,8 @@
+INSERT INTO public.markets
+(id, "name", description, status, created_at, updated_at)
+VALUES('65dcfe06-0381-47fa-8fee-64aa45fa30b4'::uuid, 'Muffato', 'Muffato', 'active', '2025-11-02 22:18:54.834', '2025-11-02 22:18:54.834')
+ON CONFLICT (id) DO NOTHING;
+INSERT INTO public.markets
+(id, "name", description, status, created_at, updated_at)
+VALUES('f7c82abd-bd7b-4bf6-a0fc-811e2d589b89'::uuid, 'Amigão', 'Amigão', 'active', '2025-11-02 22:18:54.834', '2025-11-02 22:18:54.834')
+ON CONFLICT (id) DO NOTHING;
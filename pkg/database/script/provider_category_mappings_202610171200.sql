@@ -0,0 +1,15 @@
+INSERT INTO public.provider_category_mappings (provider,external_id,category_id) VALUES
+	 ('muffato','8','52fb6e4d-d739-49fd-ac00-97215732c79f'::uuid),
+	 ('muffato','53','d1ec80e3-95af-41fd-a18e-d4cf056e0a57'::uuid),
+	 ('muffato','72','e3ab3538-bec0-4f6a-bcb5-c6d5bfae1440'::uuid),
+	 ('muffato','78','636a0d16-a13e-45e4-86cd-3e20a88ca571'::uuid),
+	 ('muffato','99','10c4f7aa-b79e-4e81-b96a-eb20b538e37c'::uuid),
+	 ('muffato','134','eeb79bb5-881b-49bb-9c5e-f6f9989e9ec9'::uuid),
+	 ('muffato','140','af852929-24a9-4a54-9b34-baeef6b3ea75'::uuid),
+	 ('muffato','168','9e9be38d-2b0d-482c-ab1a-e3127837b403'::uuid),
+	 ('muffato','181','8fe03a58-282b-4261-a447-bfbad503c7c9'::uuid),
+	 ('muffato','186','08110ad4-0381-467c-b5fd-7e56e00b5722'::uuid),
+	 ('muffato','551','7954b8d2-feaf-43b4-9887-bb011678704b'::uuid),
+	 ('muffato','607','960bd9a1-30d9-454b-b0ef-6220dff73085'::uuid),
+	 ('muffato','684','37fa473f-f323-4347-9277-6ceba95d5175'::uuid)
+ON CONFLICT (provider, external_id) DO NOTHING;
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//go:embed script/*.sql
+var embeddedSeeds embed.FS
+
+var seedFileRegex = regexp.MustCompile(`^([a-z0-9_]+)_(\d{12})\.sql$`)
+
+// Seed is a fixture script named <name>_<yyyymmddhhmm>.sql
+type Seed struct {
+	Name    string
+	Version string
+	SQL     string
+}
+
+// LoadSeeds returns the fixture scripts ordered by their timestamp suffix, so
+// mappings are loaded after the categories they reference
+func LoadSeeds(fsys fs.FS) ([]Seed, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	seeds := []Seed{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := seedFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid seed file name %q, expected <name>_<yyyymmddhhmm>.sql", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		seeds = append(seeds, Seed{Name: match[1], Version: match[2], SQL: string(content)})
+	}
+
+	sort.Slice(seeds, func(i, j int) bool {
+		if seeds[i].Version != seeds[j].Version {
+			return seeds[i].Version < seeds[j].Version
+		}
+		return seeds[i].Name < seeds[j].Name
+	})
+
+	return seeds, nil
+}
+
+// EmbeddedSeeds returns the fixtures shipped in pkg/database/script
+func EmbeddedSeeds() ([]Seed, error) {
+	sub, err := fs.Sub(embeddedSeeds, "script")
+	if err != nil {
+		return nil, err
+	}
+	return LoadSeeds(sub)
+}
+
+// RunSeeds executes each seed in its own transaction. Seeds are written with
+// ON CONFLICT clauses so running them again is harmless.
+func (db *PostgresDB) RunSeeds(ctx context.Context, log *zap.SugaredLogger, seeds []Seed) error {
+	for _, seed := range seeds {
+		if strings.TrimSpace(seed.SQL) == "" {
+			continue
+		}
+
+		tx, err := db.conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, seed.SQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("seed %s failed: %w", seed.Name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("seed %s failed: %w", seed.Name, err)
+		}
+
+		log.Infow("seed loaded", "name", seed.Name, "version", seed.Version)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"market/pkg/httpx"
+	"net/http"
+)
+
+// PoolStats is the JSON view of sql.DBStats
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// Stats retorna as estatísticas do pool de conexões
+func (db *PostgresDB) Stats() PoolStats {
+	stats := db.conn.Stats()
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// StatsHandler godoc
+// @Summary      Estatísticas do pool de conexões
+// @Description  Retorna o uso do pool de conexões com o PostgreSQL
+// @Tags         admin
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200		{object}	PoolStats
+// @Router       /admin/database/stats [get]
+func (db *PostgresDB) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	httpx.SendSuccess(w, db.Stats())
+}
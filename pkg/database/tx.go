@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by the repositories
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+var (
+	_ DBTX = (*sql.DB)(nil)
+	_ DBTX = (*sql.Tx)(nil)
+)
+
+type txKey struct{}
+
+// TxFromContext returns the transaction opened by WithTx, if any
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// WithTx runs fn inside a transaction carried by the ctx given to fn, so every
+// repository called with that ctx joins it. The transaction is committed when
+// fn returns nil and rolled back otherwise, panics included. Nested calls reuse
+// the outer transaction.
+func (db *PostgresDB) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				err = errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = fmt.Errorf("commit transaction: %w", err)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// Conn returns the transaction in ctx or the connection pool
+func (db *PostgresDB) Conn(ctx context.Context) DBTX {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return db.conn
+}
+
+// Stmt binds a statement prepared on the pool to the transaction in ctx. The
+// bound statement is closed by the driver when the transaction ends.
+func (db *PostgresDB) Stmt(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+// QueryContext executa uma consulta na transação do ctx, se houver
+func (db *PostgresDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.Conn(ctx).QueryContext(ctx, query, args...)
+}
+
+// ExecContext executa um comando na transação do ctx, se houver
+func (db *PostgresDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.Conn(ctx).ExecContext(ctx, query, args...)
+}
+
+// QueryRowContext executa uma consulta de uma linha na transação do ctx, se houver
+func (db *PostgresDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.Conn(ctx).QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"market/pkg/apperr"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used by the haversine formula
+const EarthRadiusKm = 6371.0
+
+var ErrInvalidCoordinates = apperr.Validation("invalid_coordinates", "latitude must be between -90 and 90 and longitude between -180 and 180")
+
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// BoundingBox is a lat/lng rectangle that contains a circle, used to narrow
+// down candidates in SQL before computing the exact distance
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// Validate checks that the point is a valid WGS84 coordinate
+func (p Point) Validate() error {
+	if math.IsNaN(p.Latitude) || math.IsNaN(p.Longitude) ||
+		p.Latitude < -90 || p.Latitude > 90 ||
+		p.Longitude < -180 || p.Longitude > 180 {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// DistanceKm returns the great-circle distance between two points
+func DistanceKm(a, b Point) float64 {
+	lat1 := toRadians(a.Latitude)
+	lat2 := toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Around returns the bounding box of a circle with the given radius. Near the
+// poles the longitude range is widened to the whole globe.
+func Around(center Point, radiusKm float64) BoundingBox {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+
+	box := BoundingBox{
+		MinLatitude:  math.Max(center.Latitude-dLat, -90),
+		MaxLatitude:  math.Min(center.Latitude+dLat, 90),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+
+	cosLat := math.Cos(toRadians(center.Latitude))
+	if box.MinLatitude > -90 && box.MaxLatitude < 90 && cosLat > 0 {
+		dLng := dLat / cosLat
+		if dLng < 180 {
+			box.MinLongitude = center.Longitude - dLng
+			box.MaxLongitude = center.Longitude + dLng
+		}
+	}
+
+	return box
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
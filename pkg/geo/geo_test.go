@@ -0,0 +1,50 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	maringa := Point{Latitude: -23.4205, Longitude: -51.9333}
+	londrina := Point{Latitude: -23.3045, Longitude: -51.1696}
+
+	got := DistanceKm(maringa, londrina)
+	if math.Abs(got-78.9) > 1 {
+		t.Errorf("expected ~78.9km between Maringá and Londrina, got %.2f", got)
+	}
+
+	if DistanceKm(maringa, maringa) != 0 {
+		t.Errorf("expected zero distance for the same point")
+	}
+}
+
+func TestAroundContainsRadius(t *testing.T) {
+	center := Point{Latitude: -23.4205, Longitude: -51.9333}
+	box := Around(center, 10)
+
+	edges := []Point{
+		{Latitude: box.MinLatitude, Longitude: center.Longitude},
+		{Latitude: box.MaxLatitude, Longitude: center.Longitude},
+		{Latitude: center.Latitude, Longitude: box.MinLongitude},
+		{Latitude: center.Latitude, Longitude: box.MaxLongitude},
+	}
+
+	for _, edge := range edges {
+		if d := DistanceKm(center, edge); d < 9.99 {
+			t.Errorf("bounding box edge %+v is only %.2fkm away", edge, d)
+		}
+	}
+}
+
+func TestPointValidate(t *testing.T) {
+	if err := (Point{Latitude: -23.4, Longitude: -51.9}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (Point{Latitude: 91, Longitude: 0}).Validate(); err == nil {
+		t.Errorf("expected error for latitude out of range")
+	}
+	if err := (Point{Latitude: 0, Longitude: -181}).Validate(); err == nil {
+		t.Errorf("expected error for longitude out of range")
+	}
+}
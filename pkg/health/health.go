@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"market/pkg/httpx"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDegraded Status = "degraded"
+)
+
+// CheckFunc reports a dependency as unhealthy by returning an error
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of a single check
+type Result struct {
+	Name      string `json:"name"`
+	Status    Status `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the body of the readiness endpoint
+type Report struct {
+	Status    Status    `json:"status"`
+	Checks    []Result  `json:"checks"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Handler serves the liveness and readiness probes. Only critical checks make
+// the service unready, the others just mark the report as degraded.
+type Handler struct {
+	timeout time.Duration
+	checks  []check
+}
+
+func NewHandler(timeout time.Duration) *Handler {
+	return &Handler{timeout: timeout}
+}
+
+// Register adds a dependency check to the readiness probe
+func (h *Handler) Register(name string, critical bool, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// Run executes every check concurrently, each bounded by the handler timeout
+func (h *Handler) Run(ctx context.Context) Report {
+	results := make([]Result, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results, CheckedAt: time.Now().UTC()}
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	return report
+}
+
+func (h *Handler) run(ctx context.Context, c check) (result Result) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	result = Result{Name: c.name, Status: StatusUp, Critical: c.critical}
+	started := time.Now()
+	defer func() {
+		// A panicking check must not take the probe down with it
+		if p := recover(); p != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprint(p)
+		}
+		result.LatencyMs = time.Since(started).Milliseconds()
+	}()
+
+	if err := c.fn(ctx); err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler godoc
+// @Summary      Liveness probe
+// @Description  Responde enquanto o processo estiver de pé, sem checar dependências
+// @Tags         health
+// @Produce      json
+// @Success      200		{object}	map[string]string
+// @Router       /healthz [get]
+func (h *Handler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	httpx.SendSuccess(w, map[string]Status{"status": StatusUp})
+}
+
+// ReadinessHandler godoc
+// @Summary      Readiness probe
+// @Description  Checa banco, storage e sincronização dos providers, retornando 503 quando uma dependência crítica falha
+// @Tags         health
+// @Produce      json
+// @Success      200		{object}	Report
+// @Failure      503		{object}	Report
+// @Router       /readyz [get]
+func (h *Handler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.Run(r.Context())
+	if report.Status == StatusDown {
+		httpx.SendServiceUnavailable(w, report)
+		return
+	}
+	httpx.SendSuccess(w, report)
+}
+
+// MaxAge fails when the time returned by last is missing or older than maxAge
+func MaxAge(maxAge time.Duration, last func(ctx context.Context) (*time.Time, error)) CheckFunc {
+	return func(ctx context.Context) error {
+		at, err := last(ctx)
+		if err != nil {
+			return err
+		}
+		if at == nil {
+			return fmt.Errorf("never happened")
+		}
+		if age := time.Since(*at); age > maxAge {
+			return fmt.Errorf("last one %s ago, over %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func up(context.Context) error   { return nil }
+func down(context.Context) error { return errors.New("connection refused") }
+
+func TestRunStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		critical CheckFunc
+		optional CheckFunc
+		want     Status
+	}{
+		{"all up", up, up, StatusUp},
+		{"optional down", up, down, StatusDegraded},
+		{"critical down", down, up, StatusDown},
+		{"both down", down, down, StatusDown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := NewHandler(time.Second)
+			h.Register("postgres", true, c.critical)
+			h.Register("storage", false, c.optional)
+
+			report := h.Run(context.Background())
+			if report.Status != c.want {
+				t.Fatalf("status = %s, want %s", report.Status, c.want)
+			}
+			if len(report.Checks) != 2 || report.Checks[0].Name != "postgres" || report.Checks[1].Name != "storage" {
+				t.Fatalf("checks out of registration order: %+v", report.Checks)
+			}
+		})
+	}
+}
+
+func TestRunTimeoutAndPanic(t *testing.T) {
+	h := NewHandler(10 * time.Millisecond)
+	h.Register("slow", false, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	h.Register("broken", false, func(context.Context) error { panic("boom") })
+
+	report := h.Run(context.Background())
+	for _, result := range report.Checks {
+		if result.Status != StatusDown || result.Error == "" {
+			t.Errorf("%s: got %+v, want down with error", result.Name, result)
+		}
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	h := NewHandler(time.Second)
+	h.Register("postgres", true, down)
+
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("code = %d, want 503", rec.Code)
+	}
+
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Checks[0].Error != "connection refused" {
+		t.Fatalf("error = %q", report.Checks[0].Error)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-48 * time.Hour)
+
+	cases := []struct {
+		name    string
+		last    *time.Time
+		wantErr bool
+	}{
+		{"recent", &recent, false},
+		{"too old", &old, true},
+		{"never", nil, true},
+	}
+
+	for _, c := range cases {
+		check := MaxAge(24*time.Hour, func(context.Context) (*time.Time, error) { return c.last, nil })
+		if err := check(context.Background()); (err != nil) != c.wantErr {
+			t.Errorf("%s: err = %v, wantErr %t", c.name, err, c.wantErr)
+		}
+	}
+}
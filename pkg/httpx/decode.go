@@ -0,0 +1,105 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"market/pkg/apperr"
+	"market/pkg/validate"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxBodyBytes caps the JSON bodies read by DecodeJSON, set on startup from
+// SERVER_MAX_BODY_BYTES
+var MaxBodyBytes int64 = 1 << 20
+
+var (
+	ErrEmptyBody    = apperr.Validation("empty_body", "request body is required")
+	ErrInvalidJSON  = apperr.Validation("invalid_json", "request body is not valid JSON")
+	ErrBodyTooLarge = apperr.New(apperr.KindTooLarge, "body_too_large", "request body is too large")
+)
+
+// DecodeJSON reads the request body into dst and checks its `validate` tags.
+// Bodies over MaxBodyBytes, unknown fields and trailing data are refused. The
+// returned error is ready for SendError.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return ErrInvalidJSON.WithFields(apperr.Field("body", "body must have a single JSON value"))
+	}
+	if isNil(dst) {
+		return ErrEmptyBody
+	}
+
+	return validate.Struct(dst)
+}
+
+func decodeError(err error) error {
+	var maxBytes *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.As(err, &maxBytes):
+		return ErrBodyTooLarge
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return apperr.ErrValidation.WithFields(
+			apperr.Field(typeErr.Field, fmt.Sprintf("%s must be %s", typeErr.Field, jsonType(typeErr.Type))),
+		)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		name, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		if unquoteErr != nil {
+			return ErrInvalidJSON.Wrap(err)
+		}
+		return apperr.ErrValidation.WithFields(apperr.Field(name, name+" is not allowed"))
+	default:
+		return ErrInvalidJSON.Wrap(err)
+	}
+}
+
+// jsonType names the JSON type expected for a Go type
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// isNil reports whether dst points, maybe through several pointers, to nil,
+// which happens when the body is the JSON null
+func isNil(dst any) bool {
+	v := reflect.ValueOf(dst)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package httpx
+
+import (
+	"errors"
+	"market/pkg/apperr"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeInput struct {
+	Name  string  `json:"name" validate:"required"`
+	Price float64 `json:"price" validate:"omitempty,gt=0"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		wantErr   error
+		wantField string
+	}{
+		{"valid", `{"name":"coffee","price":10}`, nil, ""},
+		{"empty body", ``, ErrEmptyBody, ""},
+		{"null body", `null`, ErrEmptyBody, ""},
+		{"malformed", `{"name":`, ErrInvalidJSON, ""},
+		{"trailing data", `{"name":"coffee"}{}`, ErrInvalidJSON, "body"},
+		{"unknown field", `{"name":"coffee","owner":"me"}`, apperr.ErrValidation, "owner"},
+		{"wrong type", `{"name":"coffee","price":"ten"}`, apperr.ErrValidation, "price"},
+		{"tag rule", `{"name":"coffee","price":-1}`, apperr.ErrValidation, "price"},
+		{"too large", `{"name":"` + strings.Repeat("a", 64) + `"}`, ErrBodyTooLarge, ""},
+	}
+
+	previous := MaxBodyBytes
+	MaxBodyBytes = 48
+	t.Cleanup(func() { MaxBodyBytes = previous })
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(c.body))
+			var dst *decodeInput
+
+			err := DecodeJSON(httptest.NewRecorder(), r, &dst)
+			if c.wantErr == nil {
+				if err != nil || dst == nil || dst.Name != "coffee" {
+					t.Fatalf("err = %v, dst = %+v", err, dst)
+				}
+				return
+			}
+
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("err = %v, want %v", err, c.wantErr)
+			}
+			if c.wantField != "" {
+				e, _ := apperr.As(err)
+				if len(e.Fields) != 1 || e.Fields[0].Field != c.wantField {
+					t.Fatalf("fields = %+v, want %q", e.Fields, c.wantField)
+				}
+			}
+		})
+	}
+}
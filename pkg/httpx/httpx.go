@@ -2,7 +2,9 @@ package httpx
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+	"strings"
 )
 
 type Status string
@@ -26,51 +28,61 @@ func SendCreated(w http.ResponseWriter, data any) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
-// SendBadRequest sends a bad request JSON response
-func SendBadRequest(w http.ResponseWriter, data any) error {
+// SendAccepted sends an accepted JSON response
+func SendAccepted(w http.ResponseWriter, data any) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
+	w.WriteHeader(http.StatusAccepted)
 	return json.NewEncoder(w).Encode(data)
 }
 
-// SendMethodNotAllowed sends a method not allowed JSON response
-func SendMethodNotAllowed(w http.ResponseWriter, data any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-	return json.NewEncoder(w).Encode(data)
+// SendBadRequest sends a bad request problem response
+func SendBadRequest(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusBadRequest, "bad_request", detail))
 }
 
-// SendInternalServerError sends an internal server error JSON response
-func SendInternalServerError(w http.ResponseWriter, message string, details any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-	return json.NewEncoder(w).Encode(map[string]any{
-		"error":   message,
-		"details": details,
-	})
+// SendMethodNotAllowed sends a method not allowed problem response
+func SendMethodNotAllowed(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusMethodNotAllowed, "method_not_allowed", detail))
 }
 
-// SendNotFound sends a not found JSON response
-func SendNotFound(w http.ResponseWriter, data any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotFound)
-	return json.NewEncoder(w).Encode(data)
+// SendInternalServerError sends an internal server error problem response.
+// The cause is never sent to the client, services already log it.
+func SendInternalServerError(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusInternalServerError, "internal_error", detail))
 }
 
-// SendUnauthorized sends an unauthorized JSON response
-func SendUnauthorized(w http.ResponseWriter, data any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	return json.NewEncoder(w).Encode(data)
+// SendNotFound sends a not found problem response
+func SendNotFound(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusNotFound, "not_found", detail))
+}
+
+// SendUnauthorized sends an unauthorized problem response
+func SendUnauthorized(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusUnauthorized, "unauthorized", detail))
+}
+
+// SendForbidden sends a forbidden problem response
+func SendForbidden(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusForbidden, "forbidden", detail))
+}
+
+// SendConflict sends a conflict problem response
+func SendConflict(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusConflict, "conflict", detail))
 }
 
-// SendForbidden sends a forbidden JSON response
-func SendForbidden(w http.ResponseWriter, data any) error {
+// SendServiceUnavailable sends a service unavailable JSON response
+func SendServiceUnavailable(w http.ResponseWriter, data any) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusForbidden)
+	w.WriteHeader(http.StatusServiceUnavailable)
 	return json.NewEncoder(w).Encode(data)
 }
 
+// SendTooManyRequests sends a too many requests problem response
+func SendTooManyRequests(w http.ResponseWriter, detail string) error {
+	return SendProblem(w, NewProblem(http.StatusTooManyRequests, "too_many_requests", detail))
+}
+
 // HTTP Method helpers - wrap handlers to only allow specific HTTP methods
 
 // Get wraps a handler to only allow GET requests
@@ -138,3 +150,38 @@ func PutOrPatch(handler http.HandlerFunc) http.HandlerFunc {
 		handler(w, r)
 	}
 }
+
+// ClientIP returns the address of the caller. trustedHops is the number of
+// proxies in front of the API that append to X-Forwarded-For, 0 ignores the
+// proxy headers. Each proxy appends the address it received the request from,
+// so the client is trustedHops entries from the right, everything to the left
+// of it was sent by the client and can be forged. Header values that are not
+// an IP address are ignored.
+func ClientIP(r *http.Request, trustedHops int) string {
+	if trustedHops > 0 {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(strings.Join(forwarded, ","), ",")
+			i := max(len(entries)-trustedHops, 0)
+			if ip := parseIP(entries[i]); ip != "" {
+				return ip
+			}
+		} else if ip := parseIP(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// parseIP returns the canonical form of value, empty when it is not an IP
+func parseIP(value string) string {
+	ip := net.ParseIP(strings.TrimSpace(value))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name      string
+		forwarded []string
+		realIP    string
+		hops      int
+		want      string
+	}{
+		{"headers not trusted", []string{"1.1.1.1"}, "", 0, "10.0.0.1"},
+		{"one proxy", []string{"9.9.9.9, 1.1.1.1"}, "", 1, "1.1.1.1"},
+		{"two proxies", []string{"9.9.9.9, 1.1.1.1, 10.0.0.2"}, "", 2, "1.1.1.1"},
+		{"repeated headers", []string{"9.9.9.9", "1.1.1.1"}, "", 1, "1.1.1.1"},
+		{"shorter chain", []string{"1.1.1.1"}, "", 2, "1.1.1.1"},
+		{"real ip", nil, "1.1.1.1", 1, "1.1.1.1"},
+		{"no headers", nil, "", 1, "10.0.0.1"},
+		{"not an ip", []string{"9.9.9.9, " + strings.Repeat("x", 100)}, "", 1, "10.0.0.1"},
+		{"real ip not an ip", nil, "localhost", 1, "10.0.0.1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			r.RemoteAddr = "10.0.0.1:5000"
+			for _, value := range c.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if c.realIP != "" {
+				r.Header.Set("X-Real-IP", c.realIP)
+			}
+
+			if got := ClientIP(r, c.hops); got != c.want {
+				t.Fatalf("ClientIP = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package httpx
+
+import (
+	"encoding/json"
+	"market/pkg/apperr"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is the RFC 7807 body sent on every error response. Code is a stable
+// identifier clients can switch on, Errors lists the invalid input fields.
+type Problem struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Detail string              `json:"detail,omitempty"`
+	Code   string              `json:"code"`
+	Errors []apperr.FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem builds a problem for status, code and detail
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// SendProblem writes p as an application/problem+json response
+func SendProblem(w http.ResponseWriter, p Problem) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// SendError maps err to a problem response. Only *apperr.Error messages reach
+// the client, anything else is reported as a generic internal error.
+func SendError(w http.ResponseWriter, err error) error {
+	return SendProblem(w, ProblemFor(err))
+}
+
+// ProblemFor builds the problem describing err
+func ProblemFor(err error) Problem {
+	e, ok := apperr.As(err)
+	if !ok || e.Kind == apperr.KindInternal {
+		return NewProblem(http.StatusInternalServerError, "internal_error", "An unexpected error occurred")
+	}
+
+	p := NewProblem(StatusFor(e.Kind), e.Code, e.Message)
+	p.Errors = e.Fields
+	return p
+}
+
+// StatusFor returns the HTTP status code of an error kind
+func StatusFor(kind apperr.Kind) int {
+	switch kind {
+	case apperr.KindNotFound:
+		return http.StatusNotFound
+	case apperr.KindConflict:
+		return http.StatusConflict
+	case apperr.KindValidation:
+		return http.StatusBadRequest
+	case apperr.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperr.KindForbidden:
+		return http.StatusForbidden
+	case apperr.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case apperr.KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case apperr.KindUpstream:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
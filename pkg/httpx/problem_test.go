@@ -0,0 +1,80 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"market/pkg/apperr"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendError(t *testing.T) {
+	notFound := apperr.NotFound("product_not_found", "product not found")
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"not found", notFound, http.StatusNotFound, "product_not_found", "product not found"},
+		{"wrapped sentinel", fmt.Errorf("error finding product: %w", notFound), http.StatusNotFound, "product_not_found", "product not found"},
+		{"conflict", apperr.Conflict("email_taken", "email already registered"), http.StatusConflict, "email_taken", "email already registered"},
+		{"forbidden", apperr.Forbidden("shared_category", "shared"), http.StatusForbidden, "shared_category", "shared"},
+		{"upstream keeps cause private", apperr.Upstream("upload_failed", "upload failed").Wrap(errors.New("s3: access denied")), http.StatusBadGateway, "upload_failed", "upload failed"},
+		{"plain error hidden", errors.New("pq: relation does not exist"), http.StatusInternalServerError, "internal_error", "An unexpected error occurred"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SendError(rec, c.err)
+
+			if rec.Code != c.wantStatus {
+				t.Fatalf("code = %d, want %d", rec.Code, c.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Fatalf("content type = %q, want %q", got, ProblemContentType)
+			}
+
+			var p Problem
+			if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if p.Status != c.wantStatus || p.Code != c.wantCode || p.Detail != c.wantDetail {
+				t.Fatalf("problem = %+v, want status %d code %q detail %q", p, c.wantStatus, c.wantCode, c.wantDetail)
+			}
+		})
+	}
+}
+
+func TestSendErrorValidationFields(t *testing.T) {
+	err := apperr.Invalid(
+		apperr.Field("email", "email is required"),
+		apperr.Field("password", "password is required"),
+	)
+
+	rec := httptest.NewRecorder()
+	SendError(rec, err)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p.Code != "validation_failed" || len(p.Errors) != 2 || p.Errors[1].Field != "password" {
+		t.Fatalf("problem = %+v", p)
+	}
+	if !errors.Is(err, apperr.ErrValidation) {
+		t.Fatal("validation error with fields should match ErrValidation")
+	}
+	if apperr.Invalid() != nil {
+		t.Fatal("Invalid without fields should be nil")
+	}
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// LogMailer logs every email and, when dir is set, also writes it as an .eml
+// file so links can be opened while testing locally
+type LogMailer struct {
+	log  *zap.SugaredLogger
+	dir  string
+	from string
+}
+
+func NewLogMailer(log *zap.SugaredLogger, dir, from string) *LogMailer {
+	return &LogMailer{log: log, dir: dir, from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if m.dir == "" {
+		m.log.Infow("email not delivered, log mailer", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", now.Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, msg.Bytes(m.from, now), 0o644); err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+
+	m.log.Infow("email written to file", "to", msg.To, "subject", msg.Subject, "path", path)
+	return nil
+}
@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"market/pkg/config"
+	"mime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer selected by MAILER_DRIVER, logging the emails when
+// no driver is configured so local environments work without a SMTP server
+func New(log *zap.SugaredLogger) Mailer {
+	env := config.Get()
+	switch env.MAILER_DRIVER {
+	case DriverSMTP:
+		return NewSMTPMailer(env.SMTP_HOST, env.SMTP_PORT, env.SMTP_USER, env.SMTP_PASSWORD, env.MAILER_FROM)
+	default:
+		return NewLogMailer(log, env.MAILER_DIR, env.MAILER_FROM)
+	}
+}
+
+// Bytes renders the message as RFC 5322 text
+func (m Message) Bytes(from string, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// validate rejects addresses and subjects that could inject headers
+func (m Message) validate() error {
+	if m.To == "" {
+		return fmt.Errorf("mailer: missing recipient")
+	}
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return fmt.Errorf("mailer: line breaks are not allowed in headers")
+	}
+	return nil
+}
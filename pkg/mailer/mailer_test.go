@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMessageBytes(t *testing.T) {
+	msg := Message{To: "ana@example.com", Subject: "Redefinição de senha", Body: "linha 1\nlinha 2"}
+	date := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	got := string(msg.Bytes("Market <no-reply@market.app>", date))
+	for _, want := range []string{
+		"From: Market <no-reply@market.app>\r\n",
+		"To: ana@example.com\r\n",
+		"Subject: =?utf-8?q?Redefini=C3=A7=C3=A3o_de_senha?=\r\n",
+		"Date: Sat, 17 Oct 2026 12:00:00 +0000\r\n",
+		"\r\n\r\nlinha 1\r\nlinha 2",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMessageRejectsHeaderInjection(t *testing.T) {
+	m := NewLogMailer(zap.NewNop().Sugar(), "", "no-reply@market.app")
+
+	err := m.Send(context.Background(), Message{To: "ana@example.com\r\nBcc: all@example.com", Subject: "x"})
+	if err == nil {
+		t.Fatal("recipient with line break accepted")
+	}
+}
+
+func TestLogMailerWritesFile(t *testing.T) {
+	dir := t.TempDir()
+	m := NewLogMailer(zap.NewNop().Sugar(), dir, "no-reply@market.app")
+
+	if err := m.Send(context.Background(), Message{To: "ana@example.com", Subject: "Reset", Body: "token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*_ana_example.com.eml"))
+	if len(files) != 1 {
+		t.Fatalf("files = %v, want one .eml", files)
+	}
+	content, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(content), "token") {
+		t.Fatalf("body not written: %s", content)
+	}
+}
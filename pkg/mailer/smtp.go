@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer sends emails through a SMTP server, using STARTTLS when offered
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, user, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	// net/smtp has no context support, so give up waiting on cancellation
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, msg.Bytes(m.from, time.Now()))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
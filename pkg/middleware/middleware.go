@@ -2,19 +2,31 @@ package middleware
 
 import (
 	"context"
-	"crypto/subtle"
+	"market/internal/domain/api_key"
 	"market/internal/domain/user"
+	"market/pkg/config"
+	"market/pkg/httpx"
 	"market/pkg/security"
-	"encoding/json"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
-// SecureMiddleware provides authentication and security headers for API endpoints
-func SecureMiddleware(next http.Handler) http.Handler {
+// APIKeyAuthenticator resolves an API key into the caller identity
+type APIKeyAuthenticator func(ctx context.Context, key string) (*security.UserAuth, error)
+
+var apiKeys APIKeyAuthenticator
+
+// UseAPIKeys lets the auth middlewares accept API keys, called once on startup
+func UseAPIKeys(authenticate APIKeyAuthenticator) {
+	apiKeys = authenticate
+}
+
+// SecureMiddleware provides authentication and security headers for API endpoints.
+// API keys are accepted when granted one of the scopes.
+func SecureMiddleware(next http.Handler, scopes ...security.Scope) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set security headers
 		setSecurityHeaders(w)
@@ -26,18 +38,21 @@ func SecureMiddleware(next http.Handler) http.Handler {
 		}
 
 		// Validate authentication
-		if !isAuthenticated(r) {
-			http.Error(w, `{"error":"Unauthorized","message":"Invalid or missing authentication token"}`, http.StatusUnauthorized)
+		userAuth, _ := authenticate(r)
+		if userAuth == nil {
+			httpx.SendUnauthorized(w, "Invalid or missing authentication token")
 			return
 		}
 
-		// Call the next handler
-		next.ServeHTTP(w, r)
+		ctx := context.WithValue(r.Context(), security.USER_KEY, *userAuth)
+		RequireScope(next.ServeHTTP, scopes...)(w, r.WithContext(ctx))
 	})
 }
 
-// AuthMiddleware provides a simple function-based middleware for direct use
-func AuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
+// AuthMiddleware authenticates the request with a JWT or an API key. API keys
+// are only accepted when granted one of the scopes, so routes without scopes
+// are reserved to people.
+func AuthMiddleware(handler http.HandlerFunc, scopes ...security.Scope) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set security headers
 		setSecurityHeaders(w)
@@ -48,50 +63,126 @@ func AuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		tokenStr := r.Header.Get("Authorization")
-		w.Header().Add("Content-Type", "application/json")
-		if tokenStr == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Token é necessário"})
+		userAuth, message := authenticate(r)
+		if userAuth == nil {
+			httpx.SendUnauthorized(w, message)
 			return
 		}
 
-		// Remove "Bearer " do token se presente
-		if len(tokenStr) > 7 && tokenStr[:7] == "Bearer " {
-			tokenStr = tokenStr[7:]
+		ctx := context.WithValue(r.Context(), security.USER_KEY, *userAuth)
+		RequireScope(handler, scopes...)(w, r.WithContext(ctx))
+	}
+}
+
+// authenticate resolves the bearer token, returning the reason when it is refused
+func authenticate(r *http.Request) (*security.UserAuth, string) {
+	tokenStr := r.Header.Get("Authorization")
+	if tokenStr == "" {
+		return nil, "Token is required"
+	}
+
+	// Strip the "Bearer " prefix when present
+	if len(tokenStr) > 7 && tokenStr[:7] == "Bearer " {
+		tokenStr = tokenStr[7:]
+	}
+
+	if api_key.IsKey(tokenStr) {
+		if apiKeys == nil {
+			return nil, "Invalid API key"
+		}
+		userAuth, err := apiKeys(r.Context(), tokenStr)
+		if err != nil || userAuth == nil {
+			return nil, "Invalid API key"
 		}
+		return userAuth, ""
+	}
+
+	claims := &user.Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, security.Keys().Keyfunc)
+
+	if err != nil || !token.Valid || !claims.VerifyIssuer(config.Get().JWT_ISSUER, true) {
+		return nil, "Invalid token"
+	}
+
+	// The user comes from the "sub" claim
+	if claims.UserID == uuid.Nil {
+		return nil, "User not identified"
+	}
 
-		claims := &user.Claims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (any, error) {
-			return []byte("sua_chave_secreta_aqui"), nil
-		})
+	// Every query is scoped by the company, tokens without one are refused
+	if claims.CompanyID == uuid.Nil {
+		return nil, "Company not identified"
+	}
+
+	return &security.UserAuth{
+		UserID:    claims.UserID,
+		CompanyID: claims.CompanyID,
+		Role:      string(claims.Role),
+	}, ""
+}
 
-		if err != nil || !token.Valid {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Token inválido"})
+// RequireScope lets API keys through only when they were granted one of the
+// scopes, people are authorized by their role instead. Must be wrapped by
+// AuthMiddleware.
+func RequireScope(handler http.HandlerFunc, scopes ...security.Scope) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userAuth, err := security.GetUser(r.Context())
+		if err != nil {
+			httpx.SendUnauthorized(w, "User not authenticated")
 			return
 		}
 
-		// Pegando o "sub" do JWT
-		userID := claims.UserID
-		if userID.String() == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Usuário não identificado"})
+		if userAuth.IsAPIKey() && !hasScope(userAuth, scopes) {
+			httpx.SendForbidden(w, "API key lacks the required scope")
 			return
 		}
 
-		user := security.UserAuth{
-			UserID:    userID,
-			CompanyID: claims.CompanyID,
+		handler(w, r)
+	}
+}
+
+func hasScope(userAuth *security.UserAuth, allowed []security.Scope) bool {
+	for _, scope := range allowed {
+		if userAuth.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole lets the request through only when the authenticated user has
+// one of the roles. Admins are always allowed. API keys carry the current role
+// of their creator and must also have passed the route scopes in
+// AuthMiddleware, which must wrap this one.
+func RequireRole(handler http.HandlerFunc, roles ...user.Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userAuth, err := security.GetUser(r.Context())
+		if err != nil {
+			httpx.SendUnauthorized(w, "User not authenticated")
+			return
 		}
 
-		ctx := context.WithValue(r.Context(), security.USER_KEY, user)
-		r = r.WithContext(ctx)
+		if !hasRole(user.Role(userAuth.Role), roles) {
+			httpx.SendForbidden(w, "Insufficient permission")
+			return
+		}
 
 		handler(w, r)
 	}
 }
 
+func hasRole(role user.Role, allowed []user.Role) bool {
+	if role == user.RoleAdmin {
+		return true
+	}
+	for _, candidate := range allowed {
+		if role == candidate {
+			return true
+		}
+	}
+	return false
+}
+
 // setSecurityHeaders adds security headers to the response
 func setSecurityHeaders(w http.ResponseWriter) {
 	// CORS headers
@@ -115,30 +206,6 @@ func setSecurityHeaders(w http.ResponseWriter) {
 	w.Header().Set("Expires", "0")
 }
 
-// isAuthenticated validates the Bearer token against the SECRET_KEY
-func isAuthenticated(r *http.Request) bool {
-
-	// Get Authorization header
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return false
-	}
-
-	// Check Bearer token format
-	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return false
-	}
-
-	// Extract token
-	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if token == "" {
-		return false
-	}
-
-	// Use constant-time comparison to prevent timing attacks
-	return subtle.ConstantTimeCompare([]byte(token), []byte("")) == 1
-}
-
 // HealthCheckMiddleware provides a simple middleware for health check endpoints (no auth required)
 func HealthCheckMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
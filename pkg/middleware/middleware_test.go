@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"market/internal/domain/user"
+	"market/pkg/security"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRequireRole(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	handler := RequireRole(ok, user.RoleCurator)
+
+	cases := []struct {
+		name string
+		role user.Role
+		auth bool
+		want int
+	}{
+		{"curator allowed", user.RoleCurator, true, http.StatusOK},
+		{"admin always allowed", user.RoleAdmin, true, http.StatusOK},
+		{"member forbidden", user.RoleMember, true, http.StatusForbidden},
+		{"token without role forbidden", "", true, http.StatusForbidden},
+		{"unauthenticated", "", false, http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/products", nil)
+			if c.auth {
+				ctx := context.WithValue(r.Context(), security.USER_KEY, security.UserAuth{Role: string(c.role)})
+				r = r.WithContext(ctx)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, r)
+			if rec.Code != c.want {
+				t.Fatalf("code = %d, want %d", rec.Code, c.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	keyID := uuid.New()
+	UseAPIKeys(func(ctx context.Context, key string) (*security.UserAuth, error) {
+		if key != "mk_valid" {
+			return nil, errors.New("invalid key")
+		}
+		return &security.UserAuth{APIKeyID: keyID, Role: string(user.RoleCurator), Scopes: []security.Scope{security.ScopePricesWrite}}, nil
+	})
+	t.Cleanup(func() { UseAPIKeys(nil) })
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	cases := []struct {
+		name    string
+		key     string
+		handler http.HandlerFunc
+		want    int
+	}{
+		{"granted scope", "mk_valid", AuthMiddleware(ok, security.ScopePricesWrite), http.StatusOK},
+		{"one of the scopes", "mk_valid", AuthMiddleware(ok, security.ScopeCatalogWrite, security.ScopePricesWrite), http.StatusOK},
+		{"missing scope", "mk_valid", AuthMiddleware(ok, security.ScopeCatalogWrite), http.StatusForbidden},
+		{"route without scopes", "mk_valid", AuthMiddleware(ok), http.StatusForbidden},
+		{"creator role allowed", "mk_valid", AuthMiddleware(RequireRole(ok, user.RoleCurator), security.ScopePricesWrite), http.StatusOK},
+		{"creator role too low", "mk_valid", AuthMiddleware(RequireRole(ok, user.RoleAdmin), security.ScopePricesWrite), http.StatusForbidden},
+		{"unknown key", "mk_other", AuthMiddleware(ok, security.ScopePricesWrite), http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/product-markets", nil)
+			r.Header.Set("Authorization", "Bearer "+c.key)
+
+			rec := httptest.NewRecorder()
+			c.handler(rec, r)
+			if rec.Code != c.want {
+				t.Fatalf("code = %d, want %d", rec.Code, c.want)
+			}
+		})
+	}
+}
+
+func TestRequireScopeIgnoresPeople(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	handler := RequireScope(ok, security.ScopeCatalogWrite)
+
+	r := httptest.NewRequest(http.MethodPost, "/products", nil)
+	r = r.WithContext(context.WithValue(r.Context(), security.USER_KEY, security.UserAuth{UserID: uuid.New(), Role: string(user.RoleMember)}))
+
+	rec := httptest.NewRecorder()
+	handler(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("code = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -1,13 +1,37 @@
 package amigao
 
+import (
+	"context"
+	"market/pkg/providers"
+
+	"github.com/google/uuid"
+)
+
+const ProviderName = "amigao"
+
 type AmigaoProvider struct {
 }
 
+var _ providers.Provider = (*AmigaoProvider)(nil)
+
 func NewAmigaoProvider() *AmigaoProvider {
 	return &AmigaoProvider{}
 }
 
-func (p *AmigaoProvider) FetchProducts() ([]string, error) {
+func (p *AmigaoProvider) Name() string {
+	return ProviderName
+}
+
+func (p *AmigaoProvider) MarketID() uuid.UUID {
+	return uuid.Nil
+}
+
+// Categories is empty until the Amigao catalog is mapped
+func (p *AmigaoProvider) Categories() []providers.Category {
+	return nil
+}
+
+func (p *AmigaoProvider) FetchProducts(ctx context.Context, category providers.Category, page int) ([]providers.Offer, error) {
 	// Implementation to fetch products from Amigao
-	return []string{"Product1", "Product2"}, nil
+	return nil, nil
 }
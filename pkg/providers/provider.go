@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Category is an external catalog category crawled by a provider. The
+// internal category of its products comes from the provider category mappings.
+type Category struct {
+	ExternalID string `json:"external_id"`
+}
+
+// Offer is a product offer normalized from a provider catalog
+type Offer struct {
+	ProviderID string `json:"provider_id"`
+	Name       string `json:"name"`
+	ImageURL   string `json:"image_url,omitempty"`
+	EAN        string `json:"ean,omitempty"`
+	// CategoryPaths are the external category paths of the product, most specific first
+	CategoryPaths []string `json:"category_paths,omitempty"`
+	Price         float64  `json:"price"`
+	ListPrice     float64  `json:"list_price"`
+	Available     bool     `json:"available"`
+}
+
+// Provider is the contract every supermarket integration implements
+type Provider interface {
+	// Name identifies the provider, e.g. "muffato"
+	Name() string
+	// MarketID is the market the provider prices belong to
+	MarketID() uuid.UUID
+	// Categories lists the external categories to crawl
+	Categories() []Category
+	// FetchProducts returns one page (starting at 0) of offers of a category.
+	// An empty page means the category has no more products.
+	FetchProducts(ctx context.Context, category Category, page int) ([]Offer, error)
+}
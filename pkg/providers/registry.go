@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry keeps the providers available to the application by name
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: map[string]Provider{},
+	}
+}
+
+// Register adds a provider, failing when the name is already taken
+func (r *Registry) Register(provider Provider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[provider.Name()]; exists {
+		return fmt.Errorf("provider %s already registered", provider.Name())
+	}
+
+	r.providers[provider.Name()] = provider
+	return nil
+}
+
+// Get returns the provider registered with the given name
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// All returns every registered provider ordered by name
+func (r *Registry) All() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]Provider, 0, len(r.providers))
+	for _, provider := range r.providers {
+		all = append(all, provider)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name() < all[j].Name()
+	})
+
+	return all
+}
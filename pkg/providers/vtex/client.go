@@ -0,0 +1,137 @@
+package vtex
+
+import (
+	"context"
+	"fmt"
+	"market/pkg/providers"
+	"market/pkg/request"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	searchPath = "/api/catalog_system/pub/products/search/"
+
+	// MaxPageSize is the largest _from/_to window accepted by VTEX
+	MaxPageSize = 50
+	// MaxResults is how deep VTEX lets the search paginate
+	MaxResults = 2500
+)
+
+// Config describes a store running on the VTEX catalog
+type Config struct {
+	Name       string               `json:"name"`
+	MarketID   uuid.UUID            `json:"market_id"`
+	BaseURL    string               `json:"base_url"`
+	PageSize   int                  `json:"page_size"`
+	Retries    int                  `json:"retries"`
+	Categories []providers.Category `json:"categories"`
+	// Filters are extra fq parameters applied to every search, e.g. "isAvailablePerSalesChannel_1:1"
+	Filters []string `json:"filters,omitempty"`
+}
+
+// Validate checks the config and fills in defaults
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("vtex provider name is required")
+	}
+	if c.MarketID == uuid.Nil {
+		return fmt.Errorf("vtex provider %s: market_id is required", c.Name)
+	}
+	if _, err := url.ParseRequestURI(c.BaseURL); err != nil {
+		return fmt.Errorf("vtex provider %s: invalid base_url: %w", c.Name, err)
+	}
+	if c.PageSize <= 0 || c.PageSize > MaxPageSize {
+		c.PageSize = MaxPageSize
+	}
+	if c.Retries <= 0 {
+		c.Retries = 3
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	return nil
+}
+
+type provider struct {
+	config Config
+	log    *zap.SugaredLogger
+}
+
+var _ providers.Provider = (*provider)(nil)
+
+// NewProvider creates a provider for a VTEX store
+func NewProvider(
+	log *zap.SugaredLogger,
+	config Config,
+) (providers.Provider, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &provider{
+		config: config,
+		log:    log,
+	}, nil
+}
+
+func (p *provider) Name() string {
+	return p.config.Name
+}
+
+func (p *provider) MarketID() uuid.UUID {
+	return p.config.MarketID
+}
+
+func (p *provider) Categories() []providers.Category {
+	return p.config.Categories
+}
+
+func (p *provider) FetchProducts(ctx context.Context, category providers.Category, page int) ([]providers.Offer, error) {
+	from := page * p.config.PageSize
+	if from >= MaxResults {
+		p.log.Warnw("vtex search limit reached", "provider", p.config.Name, "category", category.ExternalID)
+		return nil, nil
+	}
+
+	to := from + p.config.PageSize - 1
+	if to >= MaxResults {
+		to = MaxResults - 1
+	}
+
+	client := request.NewRequest[[]Product](request.RequestParams{
+		Name:    fmt.Sprintf("Fetch %s Products", p.config.Name),
+		Method:  request.GET,
+		URL:     p.searchURL(category, from, to),
+		Retries: p.config.Retries,
+	})
+
+	products, err := client.ExecuteWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s products: %w", p.config.Name, err)
+	}
+
+	offers := make([]providers.Offer, 0, len(*products))
+	for i := range *products {
+		offers = append(offers, (*products)[i].ToOffer())
+	}
+
+	p.log.Debugw("Fetched products", "provider", p.config.Name, "category", category.ExternalID, "page", page, "products", len(offers))
+	return offers, nil
+}
+
+// searchURL builds the catalog search URL filtered by category, e.g.
+// /api/catalog_system/pub/products/search/?fq=C:8&_from=0&_to=49
+func (p *provider) searchURL(category providers.Category, from, to int) string {
+	query := url.Values{}
+	query.Add("fq", "C:"+category.ExternalID)
+	for _, filter := range p.config.Filters {
+		query.Add("fq", filter)
+	}
+	query.Set("_from", strconv.Itoa(from))
+	query.Set("_to", strconv.Itoa(to))
+
+	return p.config.BaseURL + searchPath + "?" + query.Encode()
+}
@@ -0,0 +1,175 @@
+package vtex
+
+import (
+	"context"
+	"encoding/json"
+	"market/pkg/providers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func newTestProvider(t *testing.T, baseURL string) providers.Provider {
+	t.Helper()
+
+	provider, err := NewProvider(zap.NewNop().Sugar(), Config{
+		Name:     "test-store",
+		MarketID: uuid.New(),
+		BaseURL:  baseURL,
+		PageSize: 10,
+		Filters:  []string{"isAvailablePerSalesChannel_1:1"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() returned error: %v", err)
+	}
+
+	return provider
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "Missing name",
+			config:  Config{MarketID: uuid.New(), BaseURL: "https://store.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing market",
+			config:  Config{Name: "store", BaseURL: "https://store.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid base URL",
+			config:  Config{Name: "store", MarketID: uuid.New(), BaseURL: "store"},
+			wantErr: true,
+		},
+		{
+			name:   "Valid config",
+			config: Config{Name: "store", MarketID: uuid.New(), BaseURL: "https://store.example.com/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	config := Config{Name: "store", MarketID: uuid.New(), BaseURL: "https://store.example.com/", PageSize: 100}
+	config.Validate()
+	if config.PageSize != MaxPageSize {
+		t.Errorf("PageSize = %v, want %v", config.PageSize, MaxPageSize)
+	}
+	if config.BaseURL != "https://store.example.com" {
+		t.Errorf("BaseURL = %v, want trailing slash trimmed", config.BaseURL)
+	}
+}
+
+func TestFetchProductsPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != searchPath {
+			t.Errorf("Path = %v, want %v", r.URL.Path, searchPath)
+		}
+
+		query := r.URL.Query()
+		if query.Get("_from") != "20" || query.Get("_to") != "29" {
+			t.Errorf("Page window = %v-%v, want 20-29", query.Get("_from"), query.Get("_to"))
+		}
+
+		fq := query["fq"]
+		if len(fq) != 2 || fq[0] != "C:8" || fq[1] != "isAvailablePerSalesChannel_1:1" {
+			t.Errorf("fq = %v, want category and configured filter", fq)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Product{})
+	}))
+	defer server.Close()
+
+	provider := newTestProvider(t, server.URL)
+
+	offers, err := provider.FetchProducts(context.Background(), providers.Category{ExternalID: "8"}, 2)
+	if err != nil {
+		t.Fatalf("FetchProducts() returned error: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("Expected empty page, got %d offers", len(offers))
+	}
+}
+
+func TestFetchProductsSearchLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request past the VTEX search limit")
+	}))
+	defer server.Close()
+
+	provider := newTestProvider(t, server.URL)
+
+	offers, err := provider.FetchProducts(context.Background(), providers.Category{ExternalID: "8"}, MaxResults/10)
+	if err != nil {
+		t.Fatalf("FetchProducts() returned error: %v", err)
+	}
+	if offers != nil {
+		t.Errorf("Expected no offers past the search limit, got %v", offers)
+	}
+}
+
+func TestFetchProductsParsesOffers(t *testing.T) {
+	body := `[{
+		"productId": "123",
+		"productName": " Leite Integral 1L ",
+		"categories": ["/Frios e Laticínios/Leites/", "/Frios e Laticínios/"],
+		"items": [{
+			"itemId": "456",
+			"ean": "7891000100103",
+			"images": [{"imageUrl": "https://store.example.com/leite.jpg"}],
+			"sellers": [
+				{"sellerId": "2", "sellerDefault": false, "commertialOffer": {"Price": 1.0, "ListPrice": 1.0, "IsAvailable": true}},
+				{"sellerId": "1", "sellerDefault": true, "commertialOffer": {"Price": 4.49, "ListPrice": 5.99, "IsAvailable": true}}
+			]
+		}]
+	}]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider := newTestProvider(t, server.URL)
+
+	offers, err := provider.FetchProducts(context.Background(), providers.Category{ExternalID: "168"}, 0)
+	if err != nil {
+		t.Fatalf("FetchProducts() returned error: %v", err)
+	}
+	if len(offers) != 1 {
+		t.Fatalf("Expected 1 offer, got %d", len(offers))
+	}
+
+	offer := offers[0]
+	if offer.ProviderID != "123" {
+		t.Errorf("ProviderID = %v, want 123", offer.ProviderID)
+	}
+	if offer.Name != "Leite Integral 1L" {
+		t.Errorf("Name = %q, want trimmed product name", offer.Name)
+	}
+	if offer.EAN != "7891000100103" {
+		t.Errorf("EAN = %v, want 7891000100103", offer.EAN)
+	}
+	if offer.ImageURL != "https://store.example.com/leite.jpg" {
+		t.Errorf("ImageURL = %v", offer.ImageURL)
+	}
+	if offer.Price != 4.49 || offer.ListPrice != 5.99 || !offer.Available {
+		t.Errorf("Offer = %+v, want default seller commertialOffer", offer)
+	}
+}
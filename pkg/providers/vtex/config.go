@@ -0,0 +1,28 @@
+package vtex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadConfigs reads a JSON file with a list of VTEX store configs
+func LoadConfigs(path string) ([]Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vtex stores file: %w", err)
+	}
+
+	var configs []Config
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("error parsing vtex stores file: %w", err)
+	}
+
+	for i := range configs {
+		if err := configs[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return configs, nil
+}
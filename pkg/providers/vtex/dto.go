@@ -0,0 +1,134 @@
+package vtex
+
+import (
+	"market/pkg/providers"
+	"strings"
+)
+
+type CommertialOffer struct {
+	Price             float64 `json:"Price"`
+	ListPrice         float64 `json:"ListPrice"`
+	AvailableQuantity int     `json:"AvailableQuantity"`
+	IsAvailable       bool    `json:"IsAvailable"`
+}
+
+type Seller struct {
+	SellerID        string          `json:"sellerId"`
+	SellerName      string          `json:"sellerName"`
+	SellerDefault   bool            `json:"sellerDefault"`
+	CommertialOffer CommertialOffer `json:"commertialOffer"`
+}
+
+type Image struct {
+	ImageURL   string `json:"imageUrl"`
+	ImageLabel string `json:"imageLabel"`
+}
+
+type Item struct {
+	ItemID          string   `json:"itemId"`
+	Name            string   `json:"name"`
+	EAN             string   `json:"ean"`
+	MeasurementUnit string   `json:"measurementUnit"`
+	UnitMultiplier  float64  `json:"unitMultiplier"`
+	Sellers         []Seller `json:"sellers"`
+	Images          []Image  `json:"images"`
+}
+
+type Product struct {
+	ProductID     string   `json:"productId"`
+	ProductName   string   `json:"productName"`
+	Brand         string   `json:"brand"`
+	Link          string   `json:"link"`
+	Categories    []string `json:"categories"`
+	CategoriesIDs []string `json:"categoriesIds"`
+	Items         []Item   `json:"items"`
+}
+
+func (s *Item) GetDefaultImageURL() string {
+	if len(s.Images) > 0 {
+		return s.Images[0].ImageURL
+	}
+	return ""
+}
+
+// GetDefaultSeller returns the default seller of the first item that has one,
+// falling back to the first seller listed
+func (p *Product) GetDefaultSeller() *Seller {
+	var fallback *Seller
+	for i := range p.Items {
+		for j := range p.Items[i].Sellers {
+			seller := &p.Items[i].Sellers[j]
+			if seller.SellerDefault {
+				return seller
+			}
+			if fallback == nil {
+				fallback = seller
+			}
+		}
+	}
+	return fallback
+}
+
+// GetImageURL returns the first image found across the product items
+func (p *Product) GetImageURL() string {
+	for i := range p.Items {
+		if url := p.Items[i].GetDefaultImageURL(); url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// GetEAN returns the first barcode found across the product items
+func (p *Product) GetEAN() string {
+	for i := range p.Items {
+		if ean := strings.TrimSpace(p.Items[i].EAN); ean != "" {
+			return ean
+		}
+	}
+	return ""
+}
+
+// "input -> /Carnes, Aves e Peixes/Frango/",
+// "output -> Frango"
+func (p *Product) GetLastCategory() string {
+	if len(p.Categories) > 0 {
+		lastCategory := p.Categories[len(p.Categories)-1]
+		categoryParts := []rune(lastCategory)
+		startIndex := -1
+		endIndex := -1
+		for i, char := range categoryParts {
+			if char == '/' {
+				if startIndex == -1 {
+					startIndex = i
+				} else {
+					endIndex = i
+				}
+			}
+		}
+		if endIndex != -1 {
+			return string(categoryParts[startIndex+1 : endIndex])
+		}
+	}
+	return ""
+}
+
+// ToOffer normalizes the product using the default seller offer
+func (p *Product) ToOffer() providers.Offer {
+	offer := providers.Offer{
+		ProviderID: p.ProductID,
+		Name:       strings.TrimSpace(p.ProductName),
+		ImageURL:   p.GetImageURL(),
+		EAN:        p.GetEAN(),
+		// VTEX lists the categories from the most specific to the root
+		CategoryPaths: p.Categories,
+	}
+
+	if seller := p.GetDefaultSeller(); seller != nil {
+		offer.Price = seller.CommertialOffer.Price
+		offer.ListPrice = seller.CommertialOffer.ListPrice
+		offer.Available = seller.CommertialOffer.IsAvailable
+	}
+
+	return offer
+}
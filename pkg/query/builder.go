@@ -0,0 +1,101 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Builder writes the SQL of a listing. Client values only reach the query as
+// placeholders, the columns come from the schema.
+type Builder struct {
+	params *Params
+	args   []any
+}
+
+// NewBuilder starts a query whose first placeholders hold args
+func (p *Params) NewBuilder(args ...any) *Builder {
+	return &Builder{params: p, args: args}
+}
+
+// Arg adds a value and returns its placeholder
+func (b *Builder) Arg(value any) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// Args returns the values of the placeholders written so far
+func (b *Builder) Args() []any {
+	return append([]any{}, b.args...)
+}
+
+// Filters returns the filter conditions as " AND ..." clauses to append to a
+// WHERE, empty when there are no filters
+func (b *Builder) Filters() string {
+	var sql strings.Builder
+	for _, filter := range b.params.Filters {
+		column := b.params.schema.Fields[filter.Field].Column
+		sql.WriteString(" AND " + column + " " + sqlOps[filter.Op] + " " + b.Arg(filter.Value))
+	}
+	return sql.String()
+}
+
+// Page returns the cursor condition, ORDER BY and LIMIT of the page. It asks
+// for one extra row so NewPage knows whether there is a next page. Call it
+// after Filters, it completes the same WHERE.
+func (b *Builder) Page() string {
+	var sql strings.Builder
+
+	keys := b.params.sortKeys()
+	if b.params.Cursor != nil {
+		sql.WriteString(" AND " + b.after(keys))
+	}
+
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		order = append(order, b.column(key)+direction(key))
+	}
+	sql.WriteString(" ORDER BY " + strings.Join(order, ", "))
+
+	sql.WriteString(" LIMIT " + b.Arg(b.params.PageSize+1))
+	if offset := b.params.Offset(); offset > 0 {
+		sql.WriteString(" OFFSET " + b.Arg(offset))
+	}
+
+	return sql.String()
+}
+
+// after selects the rows that come after the cursor in the sort order:
+// (a > x) OR (a = x AND b < y) OR (a = x AND b = y AND id > z)
+func (b *Builder) after(keys []Sort) string {
+	placeholders := make([]string, len(keys))
+	for i := range keys {
+		placeholders[i] = b.Arg(b.params.Cursor[i])
+	}
+
+	branches := make([]string, 0, len(keys))
+	for i, key := range keys {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, b.column(keys[j])+" = "+placeholders[j])
+		}
+		op := " > "
+		if key.Desc {
+			op = " < "
+		}
+		conditions = append(conditions, b.column(key)+op+placeholders[i])
+		branches = append(branches, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return "(" + strings.Join(branches, " OR ") + ")"
+}
+
+func (b *Builder) column(s Sort) string {
+	return b.params.schema.Fields[s.Field].Column
+}
+
+func direction(s Sort) string {
+	if s.Desc {
+		return " DESC"
+	}
+	return " ASC"
+}
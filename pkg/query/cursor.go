@@ -0,0 +1,78 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// cursor is the opaque next_cursor: the sort it was made for and the values
+// of the last item for each sort key
+type cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func encodeCursor(p *Params, values []any) string {
+	data, err := json.Marshal(cursor{Sort: p.sortSpec(), Values: values})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor checks the cursor belongs to the requested sort and converts its
+// values to the types of the sort keys
+func decodeCursor(raw string, p *Params) ([]any, error) {
+	invalid := fmt.Errorf("cursor is invalid")
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, invalid
+	}
+	if c.Sort != p.sortSpec() {
+		return nil, fmt.Errorf("cursor was created for another sort")
+	}
+
+	keys := p.sortKeys()
+	if len(c.Values) != len(keys) {
+		return nil, invalid
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		value, ok := cursorValue(p.schema.Fields[key.Field], c.Values[i])
+		if !ok {
+			return nil, invalid
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func cursorValue(field Field, value any) (any, bool) {
+	if field.Type == Number {
+		n, ok := value.(float64)
+		return n, ok
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	if field.Type == Time {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err == nil
+	}
+	if field.Type == UUID {
+		v, err := parseValue(field, s)
+		return v, err == nil
+	}
+	return s, true
+}
@@ -0,0 +1,53 @@
+package query
+
+// Page is the envelope of every listing. NextCursor is empty on the last page,
+// Page only applies to offset pagination.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage builds the envelope from the rows read with Builder.Page, which
+// brings one row more than the page size. value returns the value of a sort
+// field for an item, it feeds the next cursor.
+func NewPage[T any](items []T, total int, p *Params, value func(item T, field string) any) *Page[T] {
+	page := &Page[T]{Items: items, Total: total, PageSize: p.PageSize}
+	if p.Cursor == nil {
+		page.Page = p.Page
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if len(items) > p.PageSize {
+		page.Items = items[:p.PageSize]
+		last := page.Items[len(page.Items)-1]
+
+		keys := p.sortKeys()
+		values := make([]any, len(keys))
+		for i, key := range keys {
+			values[i] = value(last, key.Field)
+		}
+		page.NextCursor = encodeCursor(p, values)
+	}
+
+	return page
+}
+
+// Map converts the items of a page, keeping the pagination
+func Map[T, U any](page *Page[T], convert func(T) U) *Page[U] {
+	items := make([]U, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return &Page[U]{
+		Items:      items,
+		Total:      page.Total,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		NextCursor: page.NextCursor,
+	}
+}
@@ -0,0 +1,273 @@
+package query
+
+import (
+	"fmt"
+	"market/pkg/apperr"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type tells how filter and cursor values of a field are parsed
+type Type int
+
+const (
+	String Type = iota
+	UUID
+	Number
+	Time
+)
+
+// Op is a filter operator, written as a suffix of the field in the query
+// string: price_lte=10 is {price, Lte, 10}, status=active is {status, Eq, active}
+type Op string
+
+const (
+	Eq  Op = "eq"
+	Lt  Op = "lt"
+	Lte Op = "lte"
+	Gt  Op = "gt"
+	Gte Op = "gte"
+)
+
+var sqlOps = map[Op]string{Eq: "=", Lt: "<", Lte: "<=", Gt: ">", Gte: ">="}
+
+// Query string parameters that are not filters
+const (
+	ParamSort     = "sort"
+	ParamPage     = "page"
+	ParamPageSize = "page_size"
+	ParamCursor   = "cursor"
+)
+
+// MaxOffset bounds offset pagination, deeper pages must be reached by cursor
+const MaxOffset = 100_000
+
+// Field is a column clients may filter or sort by. Column is the SQL
+// expression written in the query, never a client value. Sortable columns
+// must be NOT NULL so cursors can compare them.
+type Field struct {
+	Column   string
+	Type     Type
+	Ops      []Op
+	Values   []string
+	Sortable bool
+}
+
+// Schema lists what a listing accepts. Key names the unique field appended to
+// every ORDER BY, it keeps pages stable and cursors unambiguous.
+type Schema struct {
+	Fields      map[string]Field
+	Key         string
+	DefaultSort []Sort
+	DefaultSize int
+	MaxSize     int
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Params is a parsed listing request. Cursor holds the sort values of the last
+// item of the previous page, nil for offset pagination.
+type Params struct {
+	schema   *Schema
+	Filters  []Filter
+	Sort     []Sort
+	Page     int
+	PageSize int
+	Cursor   []any
+}
+
+// Parse reads filters, sort and pagination from values. Every problem is
+// reported as a field of apperr.ErrValidation.
+func Parse(values url.Values, schema *Schema) (*Params, error) {
+	p := &Params{schema: schema, Page: 1, PageSize: schema.DefaultSize}
+	var fields []apperr.FieldError
+
+	if raw := values.Get(ParamSort); raw != "" {
+		sorts, err := parseSort(raw, schema)
+		if err != nil {
+			fields = append(fields, apperr.Field(ParamSort, err.Error()))
+		}
+		p.Sort = sorts
+	} else {
+		p.Sort = slices.Clone(schema.DefaultSort)
+	}
+
+	if raw := values.Get(ParamPageSize); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			fields = append(fields, apperr.Field(ParamPageSize, "page_size must be a positive integer"))
+		}
+		p.PageSize = size
+	}
+	if schema.MaxSize > 0 && p.PageSize > schema.MaxSize {
+		p.PageSize = schema.MaxSize
+	}
+
+	if raw := values.Get(ParamPage); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			fields = append(fields, apperr.Field(ParamPage, "page must be a positive integer"))
+		} else if p.PageSize > 0 && page > MaxOffset/p.PageSize+1 {
+			fields = append(fields, apperr.Field(ParamPage, "page is too large, use cursor to go further"))
+		}
+		p.Page = page
+	}
+
+	if raw := values.Get(ParamCursor); raw != "" {
+		if values.Has(ParamPage) {
+			fields = append(fields, apperr.Field(ParamCursor, "cursor and page cannot be used together"))
+		} else if cursor, err := decodeCursor(raw, p); err != nil {
+			fields = append(fields, apperr.Field(ParamCursor, err.Error()))
+		} else {
+			p.Cursor = cursor
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch key {
+		case ParamSort, ParamPage, ParamPageSize, ParamCursor:
+			continue
+		}
+		for _, raw := range values[key] {
+			filter, err := parseFilter(key, raw, schema)
+			if err != nil {
+				fields = append(fields, apperr.Field(key, err.Error()))
+				continue
+			}
+			p.Filters = append(p.Filters, filter)
+		}
+	}
+
+	if err := apperr.Invalid(fields...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Offset returns the rows skipped by offset pagination
+func (p *Params) Offset() int {
+	if p.Cursor != nil {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
+
+func parseSort(raw string, schema *Schema) ([]Sort, error) {
+	var sorts []Sort
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		s := Sort{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+
+		field, ok := schema.Fields[s.Field]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("cannot sort by %q", s.Field)
+		}
+		if seen[s.Field] {
+			return nil, fmt.Errorf("%q is repeated", s.Field)
+		}
+		seen[s.Field] = true
+		sorts = append(sorts, s)
+	}
+
+	return sorts, nil
+}
+
+func parseFilter(key, raw string, schema *Schema) (Filter, error) {
+	name, op := key, Eq
+	if i := strings.LastIndex(key, "_"); i > 0 {
+		if _, known := sqlOps[Op(key[i+1:])]; known {
+			if _, isField := schema.Fields[key[:i]]; isField {
+				name, op = key[:i], Op(key[i+1:])
+			}
+		}
+	}
+
+	field, ok := schema.Fields[name]
+	if !ok || !slices.Contains(field.Ops, op) {
+		return Filter{}, fmt.Errorf("%s is not a supported filter", key)
+	}
+
+	value, err := parseValue(field, raw)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%s %s", key, err.Error())
+	}
+
+	return Filter{Field: name, Op: op, Value: value}, nil
+}
+
+func parseValue(field Field, raw string) (any, error) {
+	switch field.Type {
+	case UUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a valid UUID")
+		}
+		return id, nil
+	case Number:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return n, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		}
+		return t, nil
+	default:
+		if len(field.Values) > 0 && !slices.Contains(field.Values, raw) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(field.Values, ", "))
+		}
+		return raw, nil
+	}
+}
+
+// sortKeys returns the sort with the schema key appended as tiebreaker
+func (p *Params) sortKeys() []Sort {
+	keys := slices.Clone(p.Sort)
+	if !slices.ContainsFunc(keys, func(s Sort) bool { return s.Field == p.schema.Key }) {
+		keys = append(keys, Sort{Field: p.schema.Key})
+	}
+	return keys
+}
+
+// sortSpec writes the sort the way the client sends it, cursors carry it so
+// they are not reused with another order
+func (p *Params) sortSpec() string {
+	parts := make([]string, 0, len(p.Sort))
+	for _, s := range p.Sort {
+		if s.Desc {
+			parts = append(parts, "-"+s.Field)
+		} else {
+			parts = append(parts, s.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
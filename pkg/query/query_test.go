@@ -0,0 +1,163 @@
+package query
+
+import (
+	"errors"
+	"market/pkg/apperr"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testSchema = &Schema{
+	Fields: map[string]Field{
+		"id":         {Column: "t.id", Type: UUID},
+		"name":       {Column: "t.name", Type: String, Ops: []Op{Eq}, Sortable: true},
+		"status":     {Column: "t.status", Type: String, Ops: []Op{Eq}, Values: []string{"active", "inactive"}},
+		"price":      {Column: "t.price", Type: Number, Ops: []Op{Eq, Lte, Gte}, Sortable: true},
+		"created_at": {Column: "t.created_at", Type: Time, Ops: []Op{Gte}, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: []Sort{{Field: "name"}},
+	DefaultSize: 10,
+	MaxSize:     50,
+}
+
+type item struct {
+	ID    uuid.UUID
+	Name  string
+	Price float64
+}
+
+func itemValue(i item, field string) any {
+	switch field {
+	case "name":
+		return i.Name
+	case "price":
+		return i.Price
+	default:
+		return i.ID
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		wantField string
+	}{
+		{"unknown filter", "owner=me", "owner"},
+		{"unsupported op", "name_lte=a", "name_lte"},
+		{"value not allowed", "status=deleted", "status"},
+		{"not a number", "price_gte=ten", "price_gte"},
+		{"not a date", "created_at_gte=yesterday", "created_at_gte"},
+		{"not sortable", "sort=status", "sort"},
+		{"repeated sort", "sort=name,-name", "sort"},
+		{"bad page", "page=0", "page"},
+		{"page too large", "page=9223372036854775807", "page"},
+		{"bad page size", "page_size=x", "page_size"},
+		{"cursor and page", "cursor=abc&page=2", "cursor"},
+		{"bad cursor", "cursor=abc", "cursor"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(c.query)
+			_, err := Parse(values, testSchema)
+			if !errors.Is(err, apperr.ErrValidation) {
+				t.Fatalf("err = %v, want validation error", err)
+			}
+			e, _ := apperr.As(err)
+			if len(e.Fields) != 1 || e.Fields[0].Field != c.wantField {
+				t.Fatalf("fields = %+v, want %q", e.Fields, c.wantField)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	values, _ := url.ParseQuery("status=active&price_gte=2.5&created_at_gte=2026-01-02&sort=-price,name&page=3&page_size=500")
+	p, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	wantFilters := []Filter{
+		{Field: "created_at", Op: Gte, Value: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Field: "price", Op: Gte, Value: 2.5},
+		{Field: "status", Op: Eq, Value: "active"},
+	}
+	if !reflect.DeepEqual(p.Filters, wantFilters) {
+		t.Fatalf("filters = %+v, want %+v", p.Filters, wantFilters)
+	}
+	if want := []Sort{{Field: "price", Desc: true}, {Field: "name"}}; !reflect.DeepEqual(p.Sort, want) {
+		t.Fatalf("sort = %+v, want %+v", p.Sort, want)
+	}
+	if p.Page != 3 || p.PageSize != 50 || p.Offset() != 100 {
+		t.Fatalf("page = %d, page_size = %d, offset = %d", p.Page, p.PageSize, p.Offset())
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	values, _ := url.ParseQuery("status=active&page=2")
+	p, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	b := p.NewBuilder("company")
+	sql := "WHERE t.company_id = $1" + b.Filters() + b.Page()
+
+	want := "WHERE t.company_id = $1 AND t.status = $2 ORDER BY t.name ASC, t.id ASC LIMIT $3 OFFSET $4"
+	if sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+	if args := b.Args(); !reflect.DeepEqual(args, []any{"company", "active", 11, 10}) {
+		t.Fatalf("args = %v", args)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	values, _ := url.ParseQuery("sort=-price&page_size=2")
+	p, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	items := []item{
+		{ID: uuid.New(), Name: "a", Price: 30},
+		{ID: uuid.New(), Name: "b", Price: 20},
+		{ID: uuid.New(), Name: "c", Price: 10},
+	}
+	page := NewPage(items, 5, p, itemValue)
+	if len(page.Items) != 2 || page.Total != 5 || page.Page != 1 || page.NextCursor == "" {
+		t.Fatalf("page = %+v", page)
+	}
+
+	values.Set(ParamCursor, page.NextCursor)
+	next, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if want := []any{20.0, items[1].ID}; !reflect.DeepEqual(next.Cursor, want) {
+		t.Fatalf("cursor = %v, want %v", next.Cursor, want)
+	}
+
+	b := next.NewBuilder()
+	want := " AND ((t.price < $1) OR (t.price = $1 AND t.id > $2)) ORDER BY t.price DESC, t.id ASC LIMIT $3"
+	if sql := b.Page(); sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+
+	last := NewPage(items[2:], 5, next, itemValue)
+	if last.NextCursor != "" || last.Page != 0 {
+		t.Fatalf("last page = %+v", last)
+	}
+
+	values.Set(ParamSort, "name")
+	if _, err := Parse(values, testSchema); !errors.Is(err, apperr.ErrValidation) {
+		t.Fatalf("cursor reused with another sort: err = %v", err)
+	}
+}
@@ -98,11 +98,26 @@ func (r *request[T]) ExecuteWithContext(ctx context.Context) (*T, error) {
 
 		resp, err = client.Do(req)
 
-		if err == nil && resp.StatusCode != http.StatusTooManyRequests {
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
 			defer resp.Body.Close()
 			break
 		}
 
+		// Server errors are usually transient, so retry them like rate limits
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			resp.Body.Close()
+			if attempt < int(r.Retries) {
+				select {
+				case <-time.After(time.Second * 2):
+					// Continue after sleep
+				case <-ctx.Done():
+					return nil, fmt.Errorf("request cancelled during retry wait: %v", ctx.Err())
+				}
+				continue
+			}
+			return nil, fmt.Errorf("request failed after %d retries: %s", r.Retries, resp.Status)
+		}
+
 		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
 			if attempt < int(r.Retries) {
 				// Use context-aware sleep to allow cancellation during retry wait
@@ -0,0 +1,130 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"market/pkg/config"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrNoSigningKeys = errors.New("no JWT signing keys configured")
+	ErrUnknownKeyID  = errors.New("unknown JWT key id")
+
+	keyring     *Keyring
+	keyringOnce sync.Once
+	keyringErr  error
+)
+
+// Keyring holds every key accepted to verify tokens, identified by their kid,
+// and the one used to sign new tokens. Rotating means adding a new key, making
+// it active and removing the old one once the tokens signed by it expired.
+type Keyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+// ParseKeyring reads keys in the "kid:secret,kid:secret" format. The active
+// kid defaults to the first key.
+func ParseKeyring(spec, active string) (*Keyring, error) {
+	k := &Keyring{keys: map[string][]byte{}}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, secret, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid JWT key %q, expected kid:secret", kid)
+		}
+		if _, exists := k.keys[kid]; exists {
+			return nil, fmt.Errorf("duplicated JWT key id %q", kid)
+		}
+
+		k.keys[kid] = []byte(secret)
+		if k.active == "" {
+			k.active = kid
+		}
+	}
+
+	if len(k.keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+
+	if active != "" {
+		if _, ok := k.keys[active]; !ok {
+			return nil, fmt.Errorf("%w: active kid %q", ErrUnknownKeyID, active)
+		}
+		k.active = active
+	}
+
+	return k, nil
+}
+
+// LoadKeyring reads JWT_KEYS once. Outside production a random key is used
+// when none is configured, so tokens do not survive restarts.
+func LoadKeyring() (*Keyring, error) {
+	keyringOnce.Do(func() {
+		spec := config.Get().JWT_KEYS
+		if spec == "" && config.Get().ENV != "production" {
+			spec = "dev:" + NewOpaqueToken()
+		}
+		keyring, keyringErr = ParseKeyring(spec, config.Get().JWT_ACTIVE_KID)
+	})
+	return keyring, keyringErr
+}
+
+// Keys returns the keyring loaded by LoadKeyring
+func Keys() *Keyring {
+	k, err := LoadKeyring()
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// Sign signs the claims with the active key, setting its kid in the header
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = k.active
+	return token.SignedString(k.keys[k.active])
+}
+
+// Keyfunc resolves the verification key from the kid of the token, rejecting
+// other signing methods
+func (k *Keyring) Keyfunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, kid)
+	}
+	return key, nil
+}
+
+// NewOpaqueToken returns 32 random bytes encoded for use in URLs
+func NewOpaqueToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// HashToken returns the hex sha256 of an opaque token, the only form stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
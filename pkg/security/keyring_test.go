@@ -0,0 +1,74 @@
+package security
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func TestParseKeyring(t *testing.T) {
+	cases := []struct {
+		name       string
+		spec       string
+		active     string
+		wantActive string
+		wantErr    bool
+	}{
+		{"first key is active", "2025:old-secret, 2026:new-secret", "", "2025", false},
+		{"explicit active", "2025:old-secret,2026:new-secret", "2026", "2026", false},
+		{"unknown active", "2025:old-secret", "2026", "", true},
+		{"missing secret", "2025:", "", "", true},
+		{"duplicated kid", "a:x,a:y", "", "", true},
+		{"empty", " , ", "", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			k, err := ParseKeyring(c.spec, c.active)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, c.wantErr)
+			}
+			if err == nil && k.active != c.wantActive {
+				t.Fatalf("active = %s, want %s", k.active, c.wantActive)
+			}
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	claims := jwt.StandardClaims{Subject: "user", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+
+	before, _ := ParseKeyring("2025:old-secret", "")
+	token, err := before.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens signed by the previous key stay valid while it is in the keyring
+	after, _ := ParseKeyring("2025:old-secret,2026:new-secret", "2026")
+	if _, err := jwt.ParseWithClaims(token, &jwt.StandardClaims{}, after.Keyfunc); err != nil {
+		t.Fatalf("token signed by rotated key rejected: %v", err)
+	}
+
+	retired, _ := ParseKeyring("2026:new-secret", "")
+	_, err = jwt.ParseWithClaims(token, &jwt.StandardClaims{}, retired.Keyfunc)
+	if err == nil {
+		t.Fatal("token signed by retired key accepted")
+	}
+	var validationErr *jwt.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(validationErr.Inner, ErrUnknownKeyID) {
+		t.Fatalf("err = %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	token := NewOpaqueToken()
+	if token == NewOpaqueToken() {
+		t.Fatal("opaque tokens repeated")
+	}
+	if HashToken(token) != HashToken(token) || len(HashToken(token)) != 64 {
+		t.Fatal("hash is not a stable sha256 hex")
+	}
+}
@@ -0,0 +1,32 @@
+package security
+
+// Scope is a permission granted to an API key. People are authorized by their
+// role, API keys only reach the routes that accept one of their scopes.
+type Scope string
+
+const (
+	ScopeCatalogRead  Scope = "catalog:read"
+	ScopeCatalogWrite Scope = "catalog:write"
+	ScopePricesRead   Scope = "prices:read"
+	ScopePricesWrite  Scope = "prices:write"
+	ScopeSyncRun      Scope = "sync:run"
+)
+
+// Scopes lists every scope an API key can be granted
+var Scopes = []Scope{
+	ScopeCatalogRead,
+	ScopeCatalogWrite,
+	ScopePricesRead,
+	ScopePricesWrite,
+	ScopeSyncRun,
+}
+
+// IsValid checks if the scope is one of the known scopes
+func (s Scope) IsValid() bool {
+	for _, scope := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
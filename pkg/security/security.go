@@ -12,9 +12,29 @@ const (
 	USER_KEY = "USER"
 )
 
+// UserAuth is the caller identity. Requests signed with an API key carry the
+// key ID and its scopes, UserID is then the user who created the key.
 type UserAuth struct {
 	UserID    uuid.UUID
 	CompanyID uuid.UUID
+	Role      string
+	APIKeyID  uuid.UUID
+	Scopes    []Scope
+}
+
+// IsAPIKey tells whether the request was authenticated with an API key
+func (u *UserAuth) IsAPIKey() bool {
+	return u.APIKeyID != uuid.Nil
+}
+
+// HasScope tells whether the API key was granted the scope
+func (u *UserAuth) HasScope(scope Scope) bool {
+	for _, granted := range u.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
 }
 
 func GetUser(ctx context.Context) (*UserAuth, error) {
@@ -26,6 +46,17 @@ func GetUser(ctx context.Context) (*UserAuth, error) {
 	return &user, nil
 }
 
+// CompanyID returns the active company of the authenticated user. Contexts
+// without user, as the CLI and background jobs, get uuid.Nil and only reach
+// the data shared by every company.
+func CompanyID(ctx context.Context) uuid.UUID {
+	user, err := GetUser(ctx)
+	if err != nil {
+		return uuid.Nil
+	}
+	return user.CompanyID
+}
+
 func CryptoPassword(password string) ([]byte, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
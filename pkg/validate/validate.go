@@ -0,0 +1,247 @@
+package validate
+
+import (
+	"fmt"
+	"market/pkg/apperr"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Struct checks the `validate` tags of v, a struct or a pointer to one. It
+// returns apperr.ErrValidation listing every invalid field by its JSON name,
+// nil when v is valid.
+//
+// Supported rules: required, omitempty, min, max, len, gt, maxbytes, oneof,
+// url, email and dive, which applies the rules after it to every item of a
+// slice. min, max and len count the characters of strings, maxbytes their
+// bytes, for limits like the 72 bytes bcrypt hashes.
+// Nested structs are always checked.
+func Struct(v any) error {
+	var fields []apperr.FieldError
+	if err := walk(reflect.ValueOf(v), "", &fields); err != nil {
+		return err
+	}
+	return apperr.Invalid(fields...)
+}
+
+func walk(v reflect.Value, prefix string, fields *[]apperr.FieldError) error {
+	v = indirect(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+		// Embedded structs without a JSON name are flattened like encoding/json does
+		path := prefix
+		if !sf.Anonymous || sf.Tag.Get("json") != "" {
+			path = join(prefix, name)
+		}
+
+		if err := field(v.Field(i), path, splitRules(sf.Tag.Get("validate")), fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// field applies rules to value, reporting at most one failure per field
+func field(value reflect.Value, path string, rules []string, fields *[]apperr.FieldError) error {
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "omitempty":
+			if isEmpty(value) {
+				return nil
+			}
+			continue
+		case "required":
+			if isEmpty(value) {
+				*fields = append(*fields, apperr.Field(path, path+" is required"))
+				return nil
+			}
+			continue
+		case "dive":
+			items := indirect(value)
+			if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+				return fmt.Errorf("validate: dive on %s, which is not a slice", path)
+			}
+			for j := 0; j < items.Len(); j++ {
+				if err := field(items.Index(j), fmt.Sprintf("%s[%d]", path, j), rules[i+1:], fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		current := indirect(value)
+		if !current.IsValid() {
+			return nil
+		}
+
+		message, err := check(current, name, param)
+		if err != nil {
+			return fmt.Errorf("validate: %s: %w", path, err)
+		}
+		if message != "" {
+			*fields = append(*fields, apperr.Field(path, path+" "+message))
+			return nil
+		}
+	}
+
+	return walk(value, path, fields)
+}
+
+// check runs one rule, returning the failure message or "" when it passes
+func check(v reflect.Value, rule, param string) (string, error) {
+	switch rule {
+	case "min", "max", "len", "gt":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s parameter %q", rule, param)
+		}
+		size, unit, ok := measure(v)
+		if !ok {
+			return "", fmt.Errorf("%s does not apply to %s", rule, v.Kind())
+		}
+		return compare(rule, size, limit, param, unit), nil
+	case "maxbytes":
+		limit, err := strconv.Atoi(param)
+		if err != nil {
+			return "", fmt.Errorf("invalid maxbytes parameter %q", param)
+		}
+		if v.Kind() != reflect.String {
+			return "", fmt.Errorf("maxbytes does not apply to %s", v.Kind())
+		}
+		if len(v.String()) > limit {
+			return "must be at most " + param + " bytes", nil
+		}
+		return "", nil
+	case "oneof":
+		options := strings.Fields(param)
+		value := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if value == option {
+				return "", nil
+			}
+		}
+		return "must be one of " + strings.Join(options, ", "), nil
+	case "url":
+		u, err := url.ParseRequestURI(v.String())
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL", nil
+		}
+		return "", nil
+	case "email":
+		addr, err := mail.ParseAddress(v.String())
+		if err != nil || addr.Address != v.String() {
+			return "must be a valid email", nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown rule %q", rule)
+	}
+}
+
+// measure returns what min, max, len and gt compare: the length of strings and
+// slices, the value of numbers
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	}
+	return 0, "", false
+}
+
+func compare(rule string, size, limit float64, param, unit string) string {
+	switch rule {
+	case "min":
+		if size < limit {
+			return "must be at least " + param + unit
+		}
+	case "max":
+		if size > limit {
+			return "must be at most " + param + unit
+		}
+	case "len":
+		if size != limit {
+			return "must have exactly " + param + unit
+		}
+	case "gt":
+		if size <= limit {
+			return "must be greater than " + param + unit
+		}
+	}
+	return ""
+}
+
+// isEmpty reports whether a required field was left out: nil pointers, blank
+// strings, empty slices and maps, and zero values
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	}
+	return v.IsZero()
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func jsonName(sf reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return sf.Name, true
+	}
+	return name, true
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func splitRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"errors"
+	"market/pkg/apperr"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	ProductID string  `json:"product_id" validate:"required"`
+	Quantity  float64 `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+}
+
+type input struct {
+	Name     string   `json:"name" validate:"required,min=3,max=10"`
+	State    string   `json:"state" validate:"omitempty,len=2"`
+	Status   *string  `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Price    float64  `json:"price" validate:"required,gt=0"`
+	Email    string   `json:"email" validate:"omitempty,email"`
+	Image    *string  `json:"image_url" validate:"omitempty,url"`
+	Latitude *float64 `json:"latitude" validate:"omitempty,min=-90,max=90"`
+	Items    []item   `json:"items" validate:"required,max=2,dive"`
+	Password string   `json:"password" validate:"omitempty,maxbytes=72"`
+	Ignored  string   `json:"-" validate:"required"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func valid() input {
+	return input{Name: "coffee", Price: 10, Items: []item{{ProductID: "p1"}}}
+}
+
+func TestStruct(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(*input)
+		fields []string
+	}{
+		{"valid", func(*input) {}, nil},
+		{"required", func(in *input) { in.Name = ""; in.Price = 0; in.Items = nil }, []string{"name", "price", "items"}},
+		{"required blank string", func(in *input) { in.Name = "   " }, []string{"name"}},
+		{"string length", func(in *input) { in.Name = "ab"; in.State = "PRS" }, []string{"name", "state"}},
+		{"length counts runes", func(in *input) { in.Name = "açaí" }, nil},
+		{"maxbytes ascii", func(in *input) { in.Password = strings.Repeat("a", 72) }, nil},
+		{"maxbytes multibyte", func(in *input) { in.Password = strings.Repeat("é", 40) }, []string{"password"}},
+		{"oneof", func(in *input) { in.Status = ptr("deleted") }, []string{"status"}},
+		{"oneof pointer ok", func(in *input) { in.Status = ptr("active") }, nil},
+		{"gt", func(in *input) { in.Price = -1 }, []string{"price"}},
+		{"email", func(in *input) { in.Email = "John <john@example.com>" }, []string{"email"}},
+		{"url", func(in *input) { in.Image = ptr("not a url") }, []string{"image_url"}},
+		{"number range", func(in *input) { in.Latitude = ptr(-91.0) }, []string{"latitude"}},
+		{"omitempty zero pointer value", func(in *input) { in.Latitude = ptr(0.0) }, nil},
+		{"slice size", func(in *input) { in.Items = []item{{ProductID: "a"}, {ProductID: "b"}, {ProductID: "c"}} }, []string{"items"}},
+		{"dive", func(in *input) { in.Items = []item{{ProductID: "a"}, {Quantity: -1}} }, []string{"items[1].product_id", "items[1].quantity"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			in := valid()
+			c.change(&in)
+
+			err := Struct(&in)
+			if c.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, apperr.ErrValidation) {
+				t.Fatalf("err = %v, want ErrValidation", err)
+			}
+			e, _ := apperr.As(err)
+			var got []string
+			for _, f := range e.Fields {
+				got = append(got, f.Field)
+			}
+			if !reflect.DeepEqual(got, c.fields) {
+				t.Fatalf("fields = %v, want %v", got, c.fields)
+			}
+		})
+	}
+}
+
+func TestStructUnknownRule(t *testing.T) {
+	in := struct {
+		Name string `json:"name" validate:"uppercase"`
+	}{Name: "x"}
+
+	err := Struct(in)
+	if err == nil || errors.Is(err, apperr.ErrValidation) {
+		t.Fatalf("err = %v, want a programming error", err)
+	}
+}